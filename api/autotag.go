@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// AutoTagRuleRequest is the structure for auto-tag rule creation requests
+type AutoTagRuleRequest struct {
+	Tag     string `json:"tag"`
+	Type    string `json:"type"`
+	Pattern string `json:"pattern"`
+}
+
+// autoTag adds the tags matched by the workspace's auto-tag rules to an application
+// being created or updated; failing to load the rules doesn't fail the request
+func autoTag(r *http.Request, application *models.Application) {
+	rules, err := storage.ListAutoTagRules(r.Context(), application.OwnerID)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to load auto-tag rules", "error", err)
+		return
+	}
+	if added := application.ApplyAutoTags(rules, limits.MaxTags); len(added) > 0 {
+		slog.DebugContext(r.Context(), "auto-tagged application", "id", application.ID, "tags", added)
+	}
+}
+
+// ListAutoTagRulesHandler returns the auto-tag rules of the workspace
+func ListAutoTagRulesHandler(w http.ResponseWriter, r *http.Request) {
+	rules, err := storage.ListAutoTagRules(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve auto-tag rules: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    rules,
+	})
+}
+
+// CreateAutoTagRuleHandler adds an auto-tag rule to the workspace
+func CreateAutoTagRuleHandler(w http.ResponseWriter, r *http.Request) {
+	var req AutoTagRuleRequest
+	if isHtmxRequest(r) {
+		if err := r.ParseForm(); err != nil {
+			respondWithBodyError(w, r, "Invalid form data", err)
+			return
+		}
+		req.Tag, req.Type, req.Pattern = r.FormValue("tag"), r.FormValue("type"), r.FormValue("pattern")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	rule, err := models.NewAutoTagRule(workspaceID(r), req.Tag, req.Type, req.Pattern)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid auto-tag rule: "+err.Error())
+		return
+	}
+	if message := validateTagName(rule.Tag); message != "" {
+		respondWithError(w, r, http.StatusBadRequest, message)
+		return
+	}
+
+	if err := storage.CreateAutoTagRule(r.Context(), rule); err != nil {
+		if errors.Is(err, storage.ErrTooManyAutoTagRules) {
+			respondWithError(w, r, http.StatusBadRequest, fmt.Sprintf("A workspace can have at most %d auto-tag rules", storage.MaxAutoTagRules))
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to save auto-tag rule: "+err.Error())
+		}
+		return
+	}
+
+	if isHtmxRequest(r) {
+		w.Header().Set("HX-Trigger", "autoTagRulesChanged")
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Message: "Auto-tag rule created successfully",
+		Data:    rule,
+	})
+}
+
+// DeleteAutoTagRuleHandler removes an auto-tag rule from the workspace
+func DeleteAutoTagRuleHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/autotag/rules/")
+	if err := storage.DeleteAutoTagRule(r.Context(), workspaceID(r), id); err != nil {
+		if errors.Is(err, storage.ErrAutoTagRuleNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Auto-tag rule not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to delete auto-tag rule: "+err.Error())
+		}
+		return
+	}
+
+	if isHtmxRequest(r) {
+		w.Header().Set("HX-Trigger", "autoTagRulesChanged")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Auto-tag rule deleted successfully",
+	})
+}
+
+// PreviewAutoTagsHandler lists the tags the workspace's rules would add to existing
+// applications, without changing anything
+func PreviewAutoTagsHandler(w http.ResponseWriter, r *http.Request) {
+	changes, err := storage.ApplyAutoTagRules(r.Context(), workspaceID(r), limits.MaxTags, true)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to preview auto-tags: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    changes,
+	})
+}
+
+// ApplyAutoTagsHandler adds the tags matched by the workspace's rules to existing applications
+func ApplyAutoTagsHandler(w http.ResponseWriter, r *http.Request) {
+	changes, err := storage.ApplyAutoTagRules(r.Context(), workspaceID(r), limits.MaxTags, false)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to apply auto-tags: "+err.Error())
+		return
+	}
+	entries := make([]auth.AuditEntry, 0, len(changes))
+	for _, change := range changes {
+		entries = append(entries, auth.AuditEntry{Action: models.AuditApplicationUpdated, TargetID: change.ApplicationID, Detail: "auto-tagged " + strings.Join(change.Tags, ", ")})
+	}
+	auth.AuditAll(r.Context(), workspaceID(r), entries)
+
+	if isHtmxRequest(r) {
+		w.Header().Set("HX-Trigger", "autoTagRulesChanged, tagsChanged")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: fmt.Sprintf("Tagged %d applications", len(changes)),
+		Data:    changes,
+	})
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// maxBulkOperations caps the number of operations in one bulk request
+const maxBulkOperations = 500
+
+// BulkRequest is the structure for bulk operation requests
+type BulkRequest struct {
+	Operations []models.BulkOperation `json:"operations"`
+}
+
+// bulkFormOperations builds the operations of a bulk form from the list UI: one
+// operation per selected application, with actions like "set_status:rejected"
+func bulkFormOperations(r *http.Request) []models.BulkOperation {
+	action, status, _ := strings.Cut(r.FormValue("action"), ":")
+	var operations []models.BulkOperation
+	for _, id := range r.Form["ids"] {
+		operations = append(operations, models.BulkOperation{
+			ID:     id,
+			Action: action,
+			Status: status,
+			Tag:    r.FormValue("tag"),
+		})
+	}
+	return operations
+}
+
+// validateBulkOperation normalizes the tag of an operation and checks its fields, returning
+// a message if it is invalid
+func validateBulkOperation(op *models.BulkOperation) string {
+	op.Tag = models.NormalizeTag(op.Tag)
+	switch {
+	case op.ID == "":
+		return "id is required"
+	case !models.IsValidBulkAction(op.Action):
+		return fmt.Sprintf("unknown action: %q", op.Action)
+	case op.Action == models.BulkSetStatus && !models.IsValidStatus(op.Status):
+		return "invalid status value"
+	case (op.Action == models.BulkAddTag || op.Action == models.BulkRemoveTag) && op.Tag == "":
+		return "tag is required"
+	case len(op.Tag) > limits.MaxTagLength:
+		return fmt.Sprintf("tags must not exceed %d characters", limits.MaxTagLength)
+	}
+	return ""
+}
+
+// BulkApplicationsHandler applies a list of operations to applications in one write.
+// Either every operation succeeds or none is applied; the per-item results say which
+// operations failed.
+func BulkApplicationsHandler(w http.ResponseWriter, r *http.Request) {
+	var req BulkRequest
+	if isHtmxRequest(r) {
+		if err := r.ParseForm(); err != nil {
+			respondWithBodyError(w, r, "Invalid form data", err)
+			return
+		}
+		req.Operations = bulkFormOperations(r)
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	if len(req.Operations) == 0 {
+		respondWithError(w, r, http.StatusBadRequest, "At least one operation is required")
+		return
+	}
+	if len(req.Operations) > maxBulkOperations {
+		respondWithError(w, r, http.StatusBadRequest, fmt.Sprintf("A bulk request can have at most %d operations", maxBulkOperations))
+		return
+	}
+
+	results := make([]models.BulkResult, len(req.Operations))
+	valid := true
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		results[i] = models.BulkResult{Index: i, ID: op.ID, Action: op.Action}
+		if message := validateBulkOperation(op); message != "" {
+			results[i].Error = message
+			valid = false
+		}
+	}
+
+	applied := false
+	if valid {
+		var err error
+		results, applied, err = storage.ApplyBulkOperations(r.Context(), workspaceID(r), req.Operations, limits.MaxTags)
+		if err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to apply bulk operations: "+err.Error())
+			return
+		}
+	}
+	if !applied {
+		for i := range results {
+			if results[i].Error == "" {
+				results[i] = models.BulkResult{
+					Index:  i,
+					ID:     results[i].ID,
+					Action: results[i].Action,
+					Error:  "not applied because another operation failed",
+				}
+			}
+		}
+		respondWithJSON(w, http.StatusBadRequest, Response{
+			Success: false,
+			Message: "No changes were made because some operations failed",
+			Data:    results,
+		})
+		return
+	}
+
+	entries := make([]auth.AuditEntry, 0, len(req.Operations))
+	for i, op := range req.Operations {
+		switch op.Action {
+		case models.BulkSetStatus:
+			entries = append(entries, auth.AuditEntry{Action: models.AuditApplicationStatusChanged, TargetID: op.ID, Detail: results[i].PreviousStatus + " -> " + op.Status})
+		case models.BulkAddTag:
+			entries = append(entries, auth.AuditEntry{Action: models.AuditApplicationUpdated, TargetID: op.ID, Detail: "added tag " + op.Tag})
+		case models.BulkRemoveTag:
+			entries = append(entries, auth.AuditEntry{Action: models.AuditApplicationUpdated, TargetID: op.ID, Detail: "removed tag " + op.Tag})
+		case models.BulkDelete:
+			entries = append(entries, auth.AuditEntry{Action: models.AuditApplicationDeleted, TargetID: op.ID})
+		}
+	}
+	auth.AuditAll(r.Context(), workspaceID(r), entries)
+
+	// Handle HTMX response
+	if isHtmxRequest(r) {
+		w.Header().Set("HX-Refresh", "true")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: fmt.Sprintf("Applied %d operations", len(results)),
+		Data:    results,
+	})
+}
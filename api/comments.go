@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// CommentRequest is the structure for comment creation requests
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+// commentApplicationID extracts the application ID from /applications/{id}/comments
+func commentApplicationID(r *http.Request) string {
+	id := strings.TrimPrefix(r.URL.Path, "/applications/")
+	return strings.TrimSuffix(id, "/comments")
+}
+
+// ListCommentsHandler returns the comments on an application
+func ListCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	id := commentApplicationID(r)
+	if _, err := storage.GetApplicationByID(r.Context(), workspaceID(r), id); err != nil {
+		if err == storage.ErrNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Application not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
+		}
+		return
+	}
+
+	comments, err := storage.ListComments(r.Context(), workspaceID(r), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve comments: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    comments,
+	})
+}
+
+// CreateCommentHandler adds a comment to an application
+func CreateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	id := commentApplicationID(r)
+	if _, err := storage.GetApplicationByID(r.Context(), workspaceID(r), id); err != nil {
+		if err == storage.ErrNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Application not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
+		}
+		return
+	}
+
+	var req CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" || len(body) > models.MaxCommentLength {
+		respondWithError(w, r, http.StatusBadRequest, fmt.Sprintf("Comment must be between 1 and %d characters", models.MaxCommentLength))
+		return
+	}
+
+	comment := models.NewComment(workspaceID(r), id, auth.UserFromContext(r.Context()), body)
+	if err := storage.CreateComment(r.Context(), comment); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to save comment: "+err.Error())
+		return
+	}
+	auth.Audit(r.Context(), comment.WorkspaceID, models.AuditCommentCreated, id, "")
+
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Message: "Comment created successfully",
+		Data:    comment,
+	})
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// MergeCompanyRequest is the structure for requests merging another company into one
+type MergeCompanyRequest struct {
+	CompanyID string `json:"companyId"`
+}
+
+// assignCompany points an application being created or updated at the company its name
+// resolves to, creating the company if needed; failing to resolve it doesn't fail the
+// request
+func assignCompany(r *http.Request, application *models.Application) {
+	company, err := storage.ResolveCompany(r.Context(), application.OwnerID, application.Company)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to resolve company", "error", err)
+		return
+	}
+	application.CompanyID = ""
+	if company != nil {
+		application.CompanyID = company.ID
+	}
+}
+
+// companyID extracts the company ID from /companies/{id}[/applications|/merge]
+func companyID(r *http.Request) string {
+	id := strings.TrimPrefix(r.URL.Path, "/companies/")
+	id = strings.TrimSuffix(id, "/applications")
+	return strings.TrimSuffix(id, "/merge")
+}
+
+// respondWithCompanyError writes the response for a failed company lookup or change
+func respondWithCompanyError(w http.ResponseWriter, r *http.Request, err error) {
+	var nameErr *storage.CompanyNameError
+	switch {
+	case errors.Is(err, storage.ErrCompanyNotFound):
+		respondWithError(w, r, http.StatusNotFound, "Company not found")
+	case errors.Is(err, storage.ErrCompanyInUse):
+		respondWithError(w, r, http.StatusConflict, "Company has applications; merge it into another company instead")
+	case errors.As(err, &nameErr):
+		respondWithError(w, r, http.StatusConflict, nameErr.Error())
+	default:
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to access company: "+err.Error())
+	}
+}
+
+// ListCompaniesHandler returns the workspace's companies with their application history
+func ListCompaniesHandler(w http.ResponseWriter, r *http.Request) {
+	companies, err := storage.ListCompanies(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve companies: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    companies,
+	})
+}
+
+// GetCompanyHandler returns a company with its application history
+func GetCompanyHandler(w http.ResponseWriter, r *http.Request) {
+	company, err := storage.GetCompany(r.Context(), workspaceID(r), companyID(r))
+	if err != nil {
+		respondWithCompanyError(w, r, err)
+		return
+	}
+	applications, err := storage.CompanyApplications(r.Context(), company)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve applications: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    models.CompanySummary{Company: *company, History: models.NewCompanyHistory(applications)},
+	})
+}
+
+// CreateCompanyHandler adds a company to the workspace
+func CreateCompanyHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.CompanyDetails
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+	company, err := models.NewCompany(workspaceID(r), req)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := storage.CreateCompany(r.Context(), company); err != nil {
+		respondWithCompanyError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), company.WorkspaceID, models.AuditCompanyCreated, company.ID, company.Name)
+
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Message: "Company created successfully",
+		Data:    company,
+	})
+}
+
+// UpdateCompanyHandler replaces the details of a company
+func UpdateCompanyHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.CompanyDetails
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	company, err := storage.GetCompany(r.Context(), workspaceID(r), companyID(r))
+	if err != nil {
+		respondWithCompanyError(w, r, err)
+		return
+	}
+	if err := company.Update(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := storage.SaveCompany(r.Context(), company); err != nil {
+		respondWithCompanyError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), company.WorkspaceID, models.AuditCompanyUpdated, company.ID, company.Name)
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Company updated successfully",
+		Data:    company,
+	})
+}
+
+// DeleteCompanyHandler removes a company that no application refers to
+func DeleteCompanyHandler(w http.ResponseWriter, r *http.Request) {
+	company, err := storage.DeleteCompany(r.Context(), workspaceID(r), companyID(r))
+	if err != nil {
+		respondWithCompanyError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), company.WorkspaceID, models.AuditCompanyDeleted, company.ID, company.Name)
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Company deleted successfully",
+	})
+}
+
+// ListCompanyApplicationsHandler returns the applications to a company, newest first
+func ListCompanyApplicationsHandler(w http.ResponseWriter, r *http.Request) {
+	company, err := storage.GetCompany(r.Context(), workspaceID(r), companyID(r))
+	if err != nil {
+		respondWithCompanyError(w, r, err)
+		return
+	}
+
+	applications, err := storage.CompanyApplications(r.Context(), company)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve applications: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    applications,
+	})
+}
+
+// MergeCompanyHandler folds another company into this one, moving its applications over
+// and keeping its names as aliases
+func MergeCompanyHandler(w http.ResponseWriter, r *http.Request) {
+	var req MergeCompanyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+	id := companyID(r)
+	if req.CompanyID == "" || req.CompanyID == id {
+		respondWithError(w, r, http.StatusBadRequest, "companyId must name another company")
+		return
+	}
+
+	company, moved, err := storage.MergeCompanies(r.Context(), workspaceID(r), id, req.CompanyID)
+	if err != nil {
+		respondWithCompanyError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), company.WorkspaceID, models.AuditCompaniesMerged, company.ID, company.Name)
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Companies merged successfully",
+		Data: map[string]interface{}{
+			"company":      company,
+			"applications": moved,
+		},
+	})
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// compensationFields are the form fields of a compensation, after their prefix
+var compensationFields = []string{"baseMin", "baseMax", "bonus", "equity"}
+
+// compensationFromForm reads the posted compensation ("compensation.*" fields) and the
+// offer ("offer.*" fields) of an application form; the offer is left alone if the form
+// has no offer fields
+func compensationFromForm(r *http.Request, req *ApplicationRequest) error {
+	for _, prefix := range []string{"compensation", "offer"} {
+		if _, ok := r.Form[prefix+".currency"]; !ok {
+			continue
+		}
+		pay := &models.Compensation{
+			Currency: r.FormValue(prefix + ".currency"),
+			Period:   r.FormValue(prefix + ".period"),
+			Benefits: r.FormValue(prefix + ".benefits"),
+		}
+		amounts := []*int64{&pay.BaseMin, &pay.BaseMax, &pay.Bonus, &pay.Equity}
+		for i, field := range compensationFields {
+			value := strings.ReplaceAll(strings.TrimSpace(r.FormValue(prefix+"."+field)), ",", "")
+			if value == "" {
+				continue
+			}
+			amount, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s %s must be a whole number", prefix, field)
+			}
+			*amounts[i] = amount
+		}
+		if prefix == "compensation" {
+			req.Compensation = pay
+		} else {
+			req.Offer = pay
+		}
+	}
+	return nil
+}
+
+// nonZeroCompensation returns pay, or nil if nothing about it is known
+func nonZeroCompensation(pay *models.Compensation) *models.Compensation {
+	if pay == nil || pay.IsZero() {
+		return nil
+	}
+	return pay
+}
+
+// salaryFilter reads the salaryMin, salaryMax and currency query parameters of a search
+func salaryFilter(r *http.Request) (models.SalaryFilter, error) {
+	query := r.URL.Query()
+	filter := models.SalaryFilter{Currency: strings.ToUpper(strings.TrimSpace(query.Get("currency")))}
+	for name, bound := range map[string]*int64{"salaryMin": &filter.Min, "salaryMax": &filter.Max} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		amount, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || amount < 0 {
+			return filter, fmt.Errorf("%s must be a non-negative whole number", name)
+		}
+		*bound = amount
+	}
+	return filter, nil
+}
+
+// CompareOffersHandler returns the offers on accepted and in-progress applications
+// with their yearly totals, highest first within each currency
+func CompareOffersHandler(w http.ResponseWriter, r *http.Request) {
+	applications, err := storage.GetAllApplications(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve applications: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    models.CompareOffers(applications),
+	})
+}
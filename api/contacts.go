@@ -0,0 +1,216 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// LinkContactRequest is the structure for requests linking a contact to an application
+type LinkContactRequest struct {
+	ContactID string `json:"contactId"`
+}
+
+// contactID extracts the contact ID from /contacts/{id}[/applications]
+func contactID(r *http.Request) string {
+	id := strings.TrimPrefix(r.URL.Path, "/contacts/")
+	return strings.TrimSuffix(id, "/applications")
+}
+
+// respondWithContactError writes the response for a failed contact lookup or change
+func respondWithContactError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case storage.ErrContactNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Contact not found")
+	case storage.ErrContactNotLinked:
+		respondWithError(w, r, http.StatusNotFound, "Contact is not linked to this application")
+	default:
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to access contact: "+err.Error())
+	}
+}
+
+// ListContactsHandler returns the workspace's contacts, optionally filtered by ?q=
+func ListContactsHandler(w http.ResponseWriter, r *http.Request) {
+	contacts, err := storage.ListContacts(r.Context(), workspaceID(r), r.URL.Query().Get("q"))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve contacts: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    contacts,
+	})
+}
+
+// GetContactHandler returns a contact
+func GetContactHandler(w http.ResponseWriter, r *http.Request) {
+	contact, err := storage.GetContact(r.Context(), workspaceID(r), contactID(r))
+	if err != nil {
+		respondWithContactError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    contact,
+	})
+}
+
+// CreateContactHandler adds a contact to the workspace
+func CreateContactHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.ContactDetails
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+	contact, err := models.NewContact(workspaceID(r), req)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := storage.CreateContact(r.Context(), contact); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to save contact: "+err.Error())
+		return
+	}
+	auth.Audit(r.Context(), contact.WorkspaceID, models.AuditContactCreated, contact.ID, contact.Name)
+
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Message: "Contact created successfully",
+		Data:    contact,
+	})
+}
+
+// UpdateContactHandler replaces the details of a contact; its links are kept
+func UpdateContactHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.ContactDetails
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	contact, err := storage.GetContact(r.Context(), workspaceID(r), contactID(r))
+	if err != nil {
+		respondWithContactError(w, r, err)
+		return
+	}
+	if err := contact.Update(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if contact, err = storage.SaveContactDetails(r.Context(), contact); err != nil {
+		respondWithContactError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), contact.WorkspaceID, models.AuditContactUpdated, contact.ID, contact.Name)
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Contact updated successfully",
+		Data:    contact,
+	})
+}
+
+// DeleteContactHandler removes a contact and its links to applications
+func DeleteContactHandler(w http.ResponseWriter, r *http.Request) {
+	contact, err := storage.DeleteContact(r.Context(), workspaceID(r), contactID(r))
+	if err != nil {
+		respondWithContactError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), contact.WorkspaceID, models.AuditContactDeleted, contact.ID, contact.Name)
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Contact deleted successfully",
+	})
+}
+
+// ListContactApplicationsHandler returns the applications a contact is linked to
+func ListContactApplicationsHandler(w http.ResponseWriter, r *http.Request) {
+	contact, err := storage.GetContact(r.Context(), workspaceID(r), contactID(r))
+	if err != nil {
+		respondWithContactError(w, r, err)
+		return
+	}
+
+	applications, err := storage.ContactApplications(r.Context(), contact)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve applications: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    applications,
+	})
+}
+
+// ListApplicationContactsHandler returns the contacts linked to an application
+func ListApplicationContactsHandler(w http.ResponseWriter, r *http.Request) {
+	id, _ := subresourceParams(r, "contacts")
+	if !requireApplication(w, r, id) {
+		return
+	}
+
+	contacts, err := storage.ListApplicationContacts(r.Context(), workspaceID(r), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve contacts: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    contacts,
+	})
+}
+
+// LinkContactHandler links a contact to an application
+func LinkContactHandler(w http.ResponseWriter, r *http.Request) {
+	id, _ := subresourceParams(r, "contacts")
+	if !requireApplication(w, r, id) {
+		return
+	}
+
+	var req LinkContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	contact, err := storage.LinkContact(r.Context(), workspaceID(r), req.ContactID, id)
+	if err != nil {
+		respondWithContactError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), contact.WorkspaceID, models.AuditContactLinked, id, contact.Name)
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Contact linked successfully",
+		Data:    contact,
+	})
+}
+
+// UnlinkContactHandler removes the link between a contact and an application
+func UnlinkContactHandler(w http.ResponseWriter, r *http.Request) {
+	id, linkedID := subresourceParams(r, "contacts")
+	contact, err := storage.UnlinkContact(r.Context(), workspaceID(r), linkedID, id)
+	if err != nil {
+		respondWithContactError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), contact.WorkspaceID, models.AuditContactUnlinked, id, contact.Name)
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Contact unlinked successfully",
+		Data:    contact,
+	})
+}
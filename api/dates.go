@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+// datesFromForm reads the appliedAt, postedAt and closingAt date fields of an
+// application form; each is left alone if the form doesn't have it
+func datesFromForm(r *http.Request, req *ApplicationRequest) {
+	req.AppliedAt = r.FormValue("appliedAt")
+	for name, date := range map[string]**string{"postedAt": &req.PostedAt, "closingAt": &req.ClosingAt} {
+		if _, ok := r.Form[name]; ok {
+			value := r.FormValue(name)
+			*date = &value
+		}
+	}
+}
+
+// parseOptionalDate parses a date of a request; an empty date clears it
+func parseOptionalDate(name, value string) (*time.Time, string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, ""
+	}
+	date, err := models.ParseDate(value)
+	if err != nil {
+		return nil, "Invalid " + name + ": " + err.Error()
+	}
+	return &date, ""
+}
+
+// applyDates sets the dates of a request on an application and checks them, returning
+// a message for the first problem. Dates the request omits are kept, and an applied
+// date on the day the application already has keeps its time of day.
+func applyDates(req ApplicationRequest, application *models.Application) string {
+	if value := strings.TrimSpace(req.AppliedAt); value != "" {
+		applied, err := models.ParseDate(value)
+		if err != nil {
+			return "Invalid appliedAt: " + err.Error()
+		}
+		if len(value) > len(models.DateLayout) || !models.SameDay(applied, application.AppliedAt) {
+			application.SetAppliedAt(applied)
+		}
+	}
+	if req.PostedAt != nil {
+		date, message := parseOptionalDate("postedAt", *req.PostedAt)
+		if message != "" {
+			return message
+		}
+		application.PostedAt = date
+	}
+	if req.ClosingAt != nil {
+		date, message := parseOptionalDate("closingAt", *req.ClosingAt)
+		if message != "" {
+			return message
+		}
+		application.ClosingAt = date
+	}
+	if err := application.ValidateDates(time.Now()); err != nil {
+		return err.Error()
+	}
+	return ""
+}
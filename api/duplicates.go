@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// MergeRequest is the structure for application merge requests
+type MergeRequest struct {
+	DuplicateID string `json:"duplicateId"`
+}
+
+// duplicateWarning warns that an application looks like it duplicates others
+func duplicateWarning(duplicates []models.Application) Warning {
+	ids := make([]string, 0, len(duplicates))
+	for _, app := range duplicates {
+		ids = append(ids, app.ID)
+	}
+	return Warning{
+		Code:           "possible_duplicate",
+		Message:        "This application looks like a duplicate of an existing one",
+		ApplicationIDs: ids,
+	}
+}
+
+// subresourceID extracts the application ID from /applications/{id}/{name}
+func subresourceID(r *http.Request, name string) string {
+	id := strings.TrimPrefix(r.URL.Path, "/applications/")
+	return strings.TrimSuffix(id, "/"+name)
+}
+
+// subresourceParams extracts the application and item IDs from /applications/{id}/{name}[/{itemId}]
+func subresourceParams(r *http.Request, name string) (applicationID, itemID string) {
+	rest := strings.TrimPrefix(r.URL.Path, "/applications/")
+	applicationID, itemID, _ = strings.Cut(rest, "/"+name)
+	return applicationID, strings.TrimPrefix(itemID, "/")
+}
+
+// requireApplication checks that the application of a sub-resource request exists,
+// writing an error response if it doesn't
+func requireApplication(w http.ResponseWriter, r *http.Request, id string) bool {
+	if _, err := storage.GetApplicationByID(r.Context(), workspaceID(r), id); err != nil {
+		if err == storage.ErrNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Application not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
+		}
+		return false
+	}
+	return true
+}
+
+// FindDuplicatesHandler returns the applications that are likely duplicates of an application
+func FindDuplicatesHandler(w http.ResponseWriter, r *http.Request) {
+	application, err := storage.GetApplicationByID(r.Context(), workspaceID(r), subresourceID(r, "duplicates"))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Application not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
+		}
+		return
+	}
+
+	duplicates, err := storage.FindDuplicates(r.Context(), workspaceID(r), application)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to find duplicates: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    duplicates,
+	})
+}
+
+// MergeApplicationHandler folds a duplicate into an application and deletes the duplicate
+func MergeApplicationHandler(w http.ResponseWriter, r *http.Request) {
+	id := subresourceID(r, "merge")
+
+	var req MergeRequest
+	if isHtmxRequest(r) {
+		if err := r.ParseForm(); err != nil {
+			respondWithBodyError(w, r, "Invalid form data", err)
+			return
+		}
+		req.DuplicateID = r.FormValue("duplicateId")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	if req.DuplicateID == "" {
+		respondWithError(w, r, http.StatusBadRequest, "duplicateId is required")
+		return
+	}
+	if req.DuplicateID == id {
+		respondWithError(w, r, http.StatusBadRequest, "An application cannot be merged into itself")
+		return
+	}
+
+	merged, removed, err := storage.MergeApplications(r.Context(), workspaceID(r), id, req.DuplicateID, limits.MaxTags)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Application not found")
+		} else if err == storage.ErrTooManyTags {
+			respondWithError(w, r, http.StatusBadRequest, fmt.Sprintf("The merged application would have more than %d tags", limits.MaxTags))
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to merge applications: "+err.Error())
+		}
+		return
+	}
+	auth.Audit(r.Context(), merged.OwnerID, models.AuditApplicationMerged, merged.ID, removed.Company+" - "+removed.Position)
+
+	// Handle HTMX response
+	if isHtmxRequest(r) {
+		w.Header().Set("HX-Refresh", "true")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Applications merged successfully",
+		Data:    merged,
+	})
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// FieldDefinitionRequest is the structure for custom field creation requests
+type FieldDefinitionRequest struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Options []string `json:"options"`
+}
+
+// fieldsFromForm reads the custom field values ("fields.*" fields) of an application form
+func fieldsFromForm(r *http.Request, req *ApplicationRequest) {
+	for name := range r.Form {
+		if key, ok := strings.CutPrefix(name, "fields."); ok {
+			if req.Fields == nil {
+				req.Fields = map[string]interface{}{}
+			}
+			req.Fields[key] = r.FormValue(name)
+		}
+	}
+}
+
+// fieldValueString converts a custom field value of a JSON request to text; null clears
+// the field
+func fieldValueString(key string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return "", fmt.Errorf("custom field %q must be a string, number, boolean or null", key)
+}
+
+// applyFields sets the custom field values of a request on an application, checking
+// them against the workspace's definitions. Fields the request omits are kept and
+// empty values clear fields. It returns a status code and message for the first problem.
+func applyFields(r *http.Request, req ApplicationRequest, application *models.Application) (int, string) {
+	if len(req.Fields) == 0 {
+		return 0, ""
+	}
+	definitions, err := storage.ListFieldDefinitions(r.Context(), application.OwnerID)
+	if err != nil {
+		return http.StatusInternalServerError, "Failed to retrieve custom fields: " + err.Error()
+	}
+
+	values := map[string]string{}
+	for key, value := range application.Fields {
+		if _, ok := models.FindField(definitions, key); ok {
+			values[key] = value
+		}
+	}
+	for key, value := range req.Fields {
+		text, err := fieldValueString(key, value)
+		if err != nil {
+			return http.StatusBadRequest, err.Error()
+		}
+		values[key] = text
+	}
+	fields, err := models.NormalizeFields(definitions, values)
+	if err != nil {
+		return http.StatusBadRequest, "Invalid custom field: " + err.Error()
+	}
+	application.Fields = fields
+	return 0, ""
+}
+
+// fieldFilters reads the "field.{key}" query parameters of a search into filters on
+// the workspace's custom fields
+func fieldFilters(r *http.Request) ([]models.FieldFilter, error) {
+	var definitions []models.FieldDefinition
+	var filters []models.FieldFilter
+	for name, values := range r.URL.Query() {
+		key, ok := strings.CutPrefix(name, "field.")
+		if !ok || strings.TrimSpace(values[0]) == "" {
+			continue
+		}
+		if definitions == nil {
+			var err error
+			if definitions, err = storage.ListFieldDefinitions(r.Context(), workspaceID(r)); err != nil {
+				return nil, err
+			}
+		}
+		definition, ok := models.FindField(definitions, key)
+		if !ok {
+			return nil, fmt.Errorf("unknown custom field %q", key)
+		}
+		filter, err := models.NewFieldFilter(definition, values[0])
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// ListFieldDefinitionsHandler returns the custom fields of the workspace
+func ListFieldDefinitionsHandler(w http.ResponseWriter, r *http.Request) {
+	definitions, err := storage.ListFieldDefinitions(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve custom fields: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    definitions,
+	})
+}
+
+// CreateFieldDefinitionHandler adds a custom field to the workspace
+func CreateFieldDefinitionHandler(w http.ResponseWriter, r *http.Request) {
+	var req FieldDefinitionRequest
+	if isHtmxRequest(r) {
+		if err := r.ParseForm(); err != nil {
+			respondWithBodyError(w, r, "Invalid form data", err)
+			return
+		}
+		req.Name, req.Type = r.FormValue("name"), r.FormValue("type")
+		if options := strings.TrimSpace(r.FormValue("options")); options != "" {
+			req.Options = strings.Split(options, ",")
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	definition, err := models.NewFieldDefinition(workspaceID(r), req.Name, req.Type, req.Options)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid custom field: "+err.Error())
+		return
+	}
+
+	if err := storage.CreateFieldDefinition(r.Context(), definition); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrFieldKeyTaken):
+			respondWithError(w, r, http.StatusConflict, "A custom field with this name already exists")
+		case errors.Is(err, storage.ErrTooManyFieldDefinitions):
+			respondWithError(w, r, http.StatusBadRequest, fmt.Sprintf("A workspace can have at most %d custom fields", storage.MaxFieldDefinitions))
+		default:
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to save custom field: "+err.Error())
+		}
+		return
+	}
+
+	if isHtmxRequest(r) {
+		w.Header().Set("HX-Trigger", "fieldsChanged")
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Message: "Custom field created successfully",
+		Data:    definition,
+	})
+}
+
+// DeleteFieldDefinitionHandler removes a custom field and its values from the workspace
+func DeleteFieldDefinitionHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/fields/")
+	if err := storage.DeleteFieldDefinition(r.Context(), workspaceID(r), id); err != nil {
+		if errors.Is(err, storage.ErrFieldDefinitionNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Custom field not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to delete custom field: "+err.Error())
+		}
+		return
+	}
+
+	if isHtmxRequest(r) {
+		w.Header().Set("HX-Trigger", "fieldsChanged")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Custom field deleted successfully",
+	})
+}
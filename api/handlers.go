@@ -2,22 +2,31 @@ package api
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"ApplicationTracker/auth"
 	"ApplicationTracker/models"
 	"ApplicationTracker/storage"
 )
 
 // Response is a generic API response structure
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Meta    interface{} `json:"meta,omitempty"`
+	Success  bool        `json:"success"`
+	Message  string      `json:"message,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+	Meta     interface{} `json:"meta,omitempty"`
+	Warnings []Warning   `json:"warnings,omitempty"`
+}
+
+// Warning flags something about a successful request the client may want to act on
+type Warning struct {
+	Code           string   `json:"code"`
+	Message        string   `json:"message"`
+	ApplicationIDs []string `json:"applicationIds,omitempty"`
 }
 
 // ApplicationRequest is the structure for application creation/update requests
@@ -28,6 +37,24 @@ type ApplicationRequest struct {
 	URL         string   `json:"url"`
 	Status      string   `json:"status,omitempty"`
 	Tags        []string `json:"tags"`
+	// Compensation and Offer are kept as they are when omitted from updates; an empty
+	// object clears them
+	Compensation *models.Compensation `json:"compensation,omitempty"`
+	Offer        *models.Compensation `json:"offer,omitempty"`
+	// Location, WorkMode and TimeZone are also kept when omitted; an empty location,
+	// work mode or time zone clears them
+	Location *models.Location `json:"location,omitempty"`
+	WorkMode *string          `json:"workMode,omitempty"`
+	TimeZone *TimeZoneRequest `json:"timeZone,omitempty"`
+	// AppliedAt backdates when the application was sent, defaulting to now on creation;
+	// PostedAt and ClosingAt are kept when omitted and an empty date clears them. Dates
+	// are either a day such as "2024-03-15" or an RFC 3339 timestamp.
+	AppliedAt string  `json:"appliedAt,omitempty"`
+	PostedAt  *string `json:"postedAt,omitempty"`
+	ClosingAt *string `json:"closingAt,omitempty"`
+	// Fields sets custom field values by field key; omitted fields are kept and an
+	// empty value or null clears one
+	Fields map[string]interface{} `json:"fields,omitempty"`
 }
 
 // GetAllApplicationsHandler returns all applications with pagination support
@@ -63,9 +90,9 @@ func GetAllApplicationsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get paginated applications
-	applications, totalCount, err := storage.GetPaginatedApplications(page, pageSize)
+	applications, totalCount, err := storage.GetPaginatedApplications(r.Context(), workspaceID(r), page, pageSize)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve applications: "+err.Error())
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve applications: "+err.Error())
 		return
 	}
 
@@ -92,16 +119,16 @@ func GetAllApplicationsHandler(w http.ResponseWriter, r *http.Request) {
 func GetApplicationHandler(w http.ResponseWriter, r *http.Request) {
 	id := strings.TrimPrefix(r.URL.Path, "/applications/")
 	if id == "" {
-		respondWithError(w, http.StatusBadRequest, "Application ID is required")
+		respondWithError(w, r, http.StatusBadRequest, "Application ID is required")
 		return
 	}
 
-	application, err := storage.GetApplicationByID(id)
+	application, err := storage.GetApplicationByID(r.Context(), workspaceID(r), id)
 	if err != nil {
 		if err == storage.ErrNotFound {
-			respondWithError(w, http.StatusNotFound, "Application not found")
+			respondWithError(w, r, http.StatusNotFound, "Application not found")
 		} else {
-			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
 		}
 		return
 	}
@@ -119,7 +146,7 @@ func CreateApplicationHandler(w http.ResponseWriter, r *http.Request) {
 	// Handle form submissions from HTMX
 	if isHtmxRequest(r) && r.Method == http.MethodPost {
 		if err := r.ParseForm(); err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid form data: "+err.Error())
+			respondWithBodyError(w, r, "Invalid form data", err)
 			return
 		}
 
@@ -137,17 +164,31 @@ func CreateApplicationHandler(w http.ResponseWriter, r *http.Request) {
 			}
 			req.Tags = tags
 		}
+		if err := compensationFromForm(r, &req); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := locationFromForm(r, &req); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		datesFromForm(r, &req)
+		fieldsFromForm(r, &req)
 	} else {
 		// Handle JSON API requests
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+			respondWithBodyError(w, r, "Invalid request payload", err)
 			return
 		}
 	}
 
 	// Validate required fields
 	if req.Company == "" || req.Position == "" {
-		respondWithError(w, http.StatusBadRequest, "Company and position are required fields")
+		respondWithError(w, r, http.StatusBadRequest, "Company and position are required fields")
+		return
+	}
+	if message := validateApplicationRequest(&req); message != "" {
+		respondWithError(w, r, http.StatusBadRequest, message)
 		return
 	}
 
@@ -159,19 +200,44 @@ func CreateApplicationHandler(w http.ResponseWriter, r *http.Request) {
 		req.URL,
 		req.Tags,
 	)
-
-	log.Printf("Creating application: %s - %s", application.Company, application.ID)
+	application.OwnerID = workspaceID(r)
+	application.Compensation = nonZeroCompensation(req.Compensation)
+	application.Offer = nonZeroCompensation(req.Offer)
+	applyLocation(req, application)
 
 	// Set status if provided
 	if req.Status != "" {
 		application.UpdateStatus(req.Status)
 	}
+	if message := applyDates(req, application); message != "" {
+		respondWithError(w, r, http.StatusBadRequest, message)
+		return
+	}
+
+	// A backdated application has seen no activity since it was sent
+	application.LastActivityAt = application.AppliedAt
+	if code, message := applyFields(r, req, application); message != "" {
+		respondWithError(w, r, code, message)
+		return
+	}
+	autoTag(r, application)
+	assignCompany(r, application)
+
+	// Look for applications this one may duplicate
+	var warnings []Warning
+	duplicates, err := storage.FindDuplicates(r.Context(), application.OwnerID, application)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to check for duplicate applications", "error", err)
+	} else if len(duplicates) > 0 {
+		warnings = append(warnings, duplicateWarning(duplicates))
+	}
 
 	// Save to storage
-	if err := storage.SaveApplication(application); err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to save application: "+err.Error())
+	if err := storage.SaveApplication(r.Context(), application); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to save application: "+err.Error())
 		return
 	}
+	auth.Audit(r.Context(), application.OwnerID, models.AuditApplicationCreated, application.ID, "")
 
 	// Handle HTMX response
 	if isHtmxRequest(r) {
@@ -182,9 +248,10 @@ func CreateApplicationHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Handle JSON API response
 	respondWithJSON(w, http.StatusCreated, Response{
-		Success: true,
-		Message: "Application created successfully",
-		Data:    application,
+		Success:  true,
+		Message:  "Application created successfully",
+		Data:     application,
+		Warnings: warnings,
 	})
 }
 
@@ -192,17 +259,17 @@ func CreateApplicationHandler(w http.ResponseWriter, r *http.Request) {
 func UpdateApplicationHandler(w http.ResponseWriter, r *http.Request) {
 	id := strings.TrimPrefix(r.URL.Path, "/applications/")
 	if id == "" {
-		respondWithError(w, http.StatusBadRequest, "Application ID is required")
+		respondWithError(w, r, http.StatusBadRequest, "Application ID is required")
 		return
 	}
 
 	// Get existing application
-	application, err := storage.GetApplicationByID(id)
+	application, err := storage.GetApplicationByID(r.Context(), workspaceID(r), id)
 	if err != nil {
 		if err == storage.ErrNotFound {
-			respondWithError(w, http.StatusNotFound, "Application not found")
+			respondWithError(w, r, http.StatusNotFound, "Application not found")
 		} else {
-			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
 		}
 		return
 	}
@@ -212,7 +279,7 @@ func UpdateApplicationHandler(w http.ResponseWriter, r *http.Request) {
 	// Handle form submissions from HTMX
 	if isHtmxRequest(r) && r.Method == http.MethodPut {
 		if err := r.ParseForm(); err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid form data: "+err.Error())
+			respondWithBodyError(w, r, "Invalid form data", err)
 			return
 		}
 
@@ -231,14 +298,29 @@ func UpdateApplicationHandler(w http.ResponseWriter, r *http.Request) {
 			}
 			req.Tags = tags
 		}
+		if err := compensationFromForm(r, &req); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := locationFromForm(r, &req); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		datesFromForm(r, &req)
+		fieldsFromForm(r, &req)
 	} else {
 		// Parse JSON request body
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+			respondWithBodyError(w, r, "Invalid request payload", err)
 			return
 		}
 	}
 
+	if message := validateApplicationRequest(&req); message != "" {
+		respondWithError(w, r, http.StatusBadRequest, message)
+		return
+	}
+
 	// Update fields
 	if req.Company != "" {
 		application.Company = req.Company
@@ -249,20 +331,39 @@ func UpdateApplicationHandler(w http.ResponseWriter, r *http.Request) {
 	application.Description = req.Description // Allow empty description
 	application.URL = req.URL                 // Allow empty URL
 	if req.Status != "" {
-		application.Status = req.Status
+		application.UpdateStatus(req.Status)
 	}
 	if req.Tags != nil {
 		application.Tags = req.Tags
 	}
+	if req.Compensation != nil {
+		application.Compensation = nonZeroCompensation(req.Compensation)
+	}
+	if req.Offer != nil {
+		application.Offer = nonZeroCompensation(req.Offer)
+	}
+	applyLocation(req, application)
+	if message := applyDates(req, application); message != "" {
+		respondWithError(w, r, http.StatusBadRequest, message)
+		return
+	}
+	if code, message := applyFields(r, req, application); message != "" {
+		respondWithError(w, r, code, message)
+		return
+	}
+	autoTag(r, application)
+	assignCompany(r, application)
 
 	// Update timestamp
 	application.UpdatedAt = time.Now()
+	application.RecordActivity()
 
 	// Save to storage
-	if err := storage.SaveApplication(application); err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to update application: "+err.Error())
+	if err := storage.SaveApplication(r.Context(), application); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to update application: "+err.Error())
 		return
 	}
+	auth.Audit(r.Context(), application.OwnerID, models.AuditApplicationUpdated, application.ID, "")
 
 	// Handle HTMX response
 	if isHtmxRequest(r) {
@@ -285,18 +386,18 @@ func UpdateApplicationStatusHandler(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/applications/")
 	parts := strings.Split(path, "/")
 	if len(parts) != 2 || parts[1] != "status" {
-		respondWithError(w, http.StatusBadRequest, "Invalid URL format")
+		respondWithError(w, r, http.StatusBadRequest, "Invalid URL format")
 		return
 	}
 	id := parts[0]
 
 	// Get existing application
-	application, err := storage.GetApplicationByID(id)
+	application, err := storage.GetApplicationByID(r.Context(), workspaceID(r), id)
 	if err != nil {
 		if err == storage.ErrNotFound {
-			respondWithError(w, http.StatusNotFound, "Application not found")
+			respondWithError(w, r, http.StatusNotFound, "Application not found")
 		} else {
-			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
 		}
 		return
 	}
@@ -305,7 +406,7 @@ func UpdateApplicationStatusHandler(w http.ResponseWriter, r *http.Request) {
 	var status string
 	if isHtmxRequest(r) {
 		if err := r.ParseForm(); err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid form data: "+err.Error())
+			respondWithBodyError(w, r, "Invalid form data", err)
 			return
 		}
 		status = r.FormValue("status")
@@ -314,39 +415,29 @@ func UpdateApplicationStatusHandler(w http.ResponseWriter, r *http.Request) {
 			Status string `json:"status"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+			respondWithBodyError(w, r, "Invalid request payload", err)
 			return
 		}
 		status = req.Status
 	}
 
 	// Validate status
-	validStatuses := []string{
-		models.ApplicationStatus.Applied,
-		models.ApplicationStatus.InProgress,
-		models.ApplicationStatus.Accepted,
-		models.ApplicationStatus.Rejected,
-	}
-	valid := false
-	for _, s := range validStatuses {
-		if status == s {
-			valid = true
-			break
-		}
-	}
-	if !valid {
-		respondWithError(w, http.StatusBadRequest, "Invalid status value")
+	if !models.IsValidStatus(status) {
+		respondWithError(w, r, http.StatusBadRequest, "Invalid status value")
 		return
 	}
 
 	// Update status
+	previousStatus := application.Status
 	application.UpdateStatus(status)
+	application.RecordActivity()
 
 	// Save to storage
-	if err := storage.SaveApplication(application); err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to update application: "+err.Error())
+	if err := storage.SaveApplication(r.Context(), application); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to update application: "+err.Error())
 		return
 	}
+	auth.Audit(r.Context(), application.OwnerID, models.AuditApplicationStatusChanged, application.ID, previousStatus+" -> "+status)
 
 	// Handle HTMX response
 	if isHtmxRequest(r) {
@@ -367,18 +458,19 @@ func UpdateApplicationStatusHandler(w http.ResponseWriter, r *http.Request) {
 func DeleteApplicationHandler(w http.ResponseWriter, r *http.Request) {
 	id := strings.TrimPrefix(r.URL.Path, "/applications/")
 	if id == "" {
-		respondWithError(w, http.StatusBadRequest, "Application ID is required")
+		respondWithError(w, r, http.StatusBadRequest, "Application ID is required")
 		return
 	}
 
-	if err := storage.DeleteApplication(id); err != nil {
+	if err := storage.DeleteApplication(r.Context(), workspaceID(r), id); err != nil {
 		if err == storage.ErrNotFound {
-			respondWithError(w, http.StatusNotFound, "Application not found")
+			respondWithError(w, r, http.StatusNotFound, "Application not found")
 		} else {
-			respondWithError(w, http.StatusInternalServerError, "Failed to delete application: "+err.Error())
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to delete application: "+err.Error())
 		}
 		return
 	}
+	auth.Audit(r.Context(), workspaceID(r), models.AuditApplicationDeleted, id, "")
 
 	respondWithJSON(w, http.StatusOK, Response{
 		Success: true,
@@ -386,7 +478,8 @@ func DeleteApplicationHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// SearchApplicationsHandler searches applications by query and tags
+// SearchApplicationsHandler searches applications by query, tags, yearly base pay,
+// work mode, location, time zone and custom fields
 func SearchApplicationsHandler(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 
@@ -395,12 +488,30 @@ func SearchApplicationsHandler(w http.ResponseWriter, r *http.Request) {
 	if tagsParam := r.URL.Query().Get("tags"); tagsParam != "" {
 		tags = strings.Split(tagsParam, ",")
 	}
+	filter, err := salaryFilter(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	place, err := locationFilter(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields, err := fieldFilters(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	applications, err := storage.SearchApplications(query, tags)
+	applications, err := storage.SearchApplications(r.Context(), workspaceID(r), query, tags, true)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to search applications: "+err.Error())
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to search applications: "+err.Error())
 		return
 	}
+	applications = models.FilterBySalary(applications, filter)
+	applications = models.FilterByLocation(applications, place)
+	applications = models.FilterByFields(applications, fields)
 
 	respondWithJSON(w, http.StatusOK, Response{
 		Success: true,
@@ -409,9 +520,13 @@ func SearchApplicationsHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // respondWithError sends an error response and logs the error
-func respondWithError(w http.ResponseWriter, code int, message string) {
-	// Log the error
-	log.Printf("ERROR: %s (Status: %d)", message, code)
+func respondWithError(w http.ResponseWriter, r *http.Request, code int, message string) {
+	// Log the error; client errors are expected and only worth a warning
+	level := slog.LevelWarn
+	if code >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	slog.Log(r.Context(), level, message, "status", code, "method", r.Method, "path", r.URL.Path)
 
 	respondWithJSON(w, code, Response{
 		Success: false,
@@ -419,6 +534,11 @@ func respondWithError(w http.ResponseWriter, code int, message string) {
 	})
 }
 
+// workspaceID returns the ID of the workspace the request acts on; handlers run behind requireAuth
+func workspaceID(r *http.Request) string {
+	return auth.WorkspaceFromContext(r.Context()).ID
+}
+
 // isHtmxRequest checks if the request is from HTMX
 func isHtmxRequest(r *http.Request) bool {
 	return r.Header.Get("HX-Request") == "true"
@@ -428,7 +548,7 @@ func isHtmxRequest(r *http.Request) bool {
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	response, err := json.Marshal(payload)
 	if err != nil {
-		log.Printf("ERROR: Failed to marshal JSON response: %v", err)
+		slog.Error("failed to marshal JSON response", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("Failed to marshal JSON response"))
 		return
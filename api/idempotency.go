@@ -0,0 +1,171 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+const (
+	// idempotencyKeyHeader lets clients retry a request without repeating its effect
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayHeader marks a response replayed from an earlier request
+	idempotentReplayHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength caps the length of idempotency keys
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored with an idempotent response
+var replayedHeaders = []string{"Content-Type", "HX-Redirect"}
+
+var (
+	// idempotencyTTL is how long responses are kept for replay; set by SetupRouter
+	idempotencyTTL = 24 * time.Hour
+
+	// inFlight holds the idempotency keys of requests still being handled
+	inFlightMutex = &sync.Mutex{}
+	inFlight      = map[string]bool{}
+)
+
+// bufferedResponse passes a response through while keeping a copy of it
+type bufferedResponse struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status code before writing it
+func (b *bufferedResponse) WriteHeader(code int) {
+	b.status = code
+	b.ResponseWriter.WriteHeader(code)
+}
+
+// Write copies the body before writing it
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	b.body.Write(data)
+	return b.ResponseWriter.Write(data)
+}
+
+// idempotent stores the first response to a request carrying an Idempotency-Key header
+// and replays it when the request is retried with the same key and payload. Reusing a
+// key with a different payload, or while the first request is still running, is a 409.
+// Server errors are not stored so the request can be retried.
+func idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondWithError(w, r, http.StatusBadRequest, "Idempotency-Key must not exceed 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			respondWithBodyError(w, r, "Invalid request payload", err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		userID := auth.UserFromContext(ctx).ID
+		hash := requestHash(r, body)
+
+		if replayStored(w, r, userID, key, hash) {
+			return
+		}
+
+		lock := userID + "\x00" + key
+		inFlightMutex.Lock()
+		if inFlight[lock] {
+			inFlightMutex.Unlock()
+			respondWithError(w, r, http.StatusConflict, "A request with this Idempotency-Key is still being processed")
+			return
+		}
+		inFlight[lock] = true
+		inFlightMutex.Unlock()
+		defer func() {
+			inFlightMutex.Lock()
+			delete(inFlight, lock)
+			inFlightMutex.Unlock()
+		}()
+
+		// A request with the same key may have finished between the lookup and the lock
+		if replayStored(w, r, userID, key, hash) {
+			return
+		}
+
+		buffered := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next(buffered, r)
+		if buffered.status >= http.StatusInternalServerError {
+			return
+		}
+
+		now := time.Now()
+		record := &models.IdempotencyRecord{
+			Key:         key,
+			UserID:      userID,
+			RequestHash: hash,
+			Status:      buffered.status,
+			Header:      map[string]string{},
+			Body:        buffered.body.Bytes(),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(idempotencyTTL),
+		}
+		for _, name := range replayedHeaders {
+			if value := w.Header().Get(name); value != "" {
+				record.Header[name] = value
+			}
+		}
+		if err := storage.SaveIdempotencyRecord(ctx, record); err != nil {
+			slog.ErrorContext(ctx, "failed to store idempotent response", "error", err)
+		}
+	}
+}
+
+// replayStored responds with the stored response to the key, or with a 409 if the key
+// was used for a different request, and reports whether it responded
+func replayStored(w http.ResponseWriter, r *http.Request, userID, key, hash string) bool {
+	ctx := r.Context()
+	record, err := storage.GetIdempotencyRecord(ctx, userID, key)
+	switch {
+	case errors.Is(err, storage.ErrIdempotencyRecordNotFound):
+		return false
+	case err != nil:
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to look up idempotency key")
+	case record.RequestHash != hash:
+		respondWithError(w, r, http.StatusConflict, "Idempotency-Key was already used with a different request")
+	default:
+		slog.InfoContext(ctx, "replaying idempotent response", "status", record.Status)
+		for name, value := range record.Header {
+			w.Header().Set(name, value)
+		}
+		w.Header().Set(idempotentReplayHeader, "true")
+		w.WriteHeader(record.Status)
+		w.Write(record.Body)
+	}
+	return true
+}
+
+// requestHash fingerprints the parts of a request that must match for a replay
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	for _, part := range []string{r.Method, r.URL.Path, workspaceID(r), r.Header.Get("Content-Type")} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+const (
+	// defaultUpcomingDays is how far ahead upcoming interviews are listed by default
+	defaultUpcomingDays = 14
+	// maxUpcomingDays bounds how far ahead upcoming interviews can be listed
+	maxUpcomingDays = 365
+)
+
+// respondWithInterviewError writes the response for a failed interview lookup
+func respondWithInterviewError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == storage.ErrInterviewNotFound {
+		respondWithError(w, r, http.StatusNotFound, "Interview not found")
+	} else {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve interview: "+err.Error())
+	}
+}
+
+// ListInterviewsHandler returns the interviews of an application, earliest first
+func ListInterviewsHandler(w http.ResponseWriter, r *http.Request) {
+	id, _ := subresourceParams(r, "interviews")
+	if !requireApplication(w, r, id) {
+		return
+	}
+
+	interviews, err := storage.ListInterviews(r.Context(), workspaceID(r), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve interviews: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    interviews,
+	})
+}
+
+// GetInterviewHandler returns an interview of an application
+func GetInterviewHandler(w http.ResponseWriter, r *http.Request) {
+	id, interviewID := subresourceParams(r, "interviews")
+	interview, err := storage.GetInterview(r.Context(), workspaceID(r), id, interviewID)
+	if err != nil {
+		respondWithInterviewError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    interview,
+	})
+}
+
+// CreateInterviewHandler schedules an interview for an application. The first interview
+// of an application that is still "applied" moves it to "in_progress".
+func CreateInterviewHandler(w http.ResponseWriter, r *http.Request) {
+	id, _ := subresourceParams(r, "interviews")
+	application, err := storage.GetApplicationByID(r.Context(), workspaceID(r), id)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Application not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve application: "+err.Error())
+		}
+		return
+	}
+
+	var req models.InterviewDetails
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+	interview, err := models.NewInterview(workspaceID(r), id, req)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	previousStatus, err := storage.ScheduleInterview(r.Context(), application, interview)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to save interview: "+err.Error())
+		return
+	}
+	auth.Audit(r.Context(), interview.WorkspaceID, models.AuditInterviewScheduled, id, interview.Round)
+
+	message := "Interview scheduled successfully"
+	if previousStatus != "" {
+		auth.Audit(r.Context(), application.OwnerID, models.AuditApplicationStatusChanged, id, previousStatus+" -> "+application.Status)
+		message = "Interview scheduled successfully; the application is now in progress"
+	}
+
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Message: message,
+		Data:    interview,
+	})
+}
+
+// UpdateInterviewHandler replaces the details of an interview, such as its outcome
+func UpdateInterviewHandler(w http.ResponseWriter, r *http.Request) {
+	id, interviewID := subresourceParams(r, "interviews")
+	interview, err := storage.GetInterview(r.Context(), workspaceID(r), id, interviewID)
+	if err != nil {
+		respondWithInterviewError(w, r, err)
+		return
+	}
+
+	var req models.InterviewDetails
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+	if err := interview.Update(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := storage.SaveInterview(r.Context(), interview); err != nil {
+		respondWithInterviewError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), interview.WorkspaceID, models.AuditInterviewUpdated, id, interview.Round+" "+interview.Outcome)
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Interview updated successfully",
+		Data:    interview,
+	})
+}
+
+// DeleteInterviewHandler removes an interview from an application
+func DeleteInterviewHandler(w http.ResponseWriter, r *http.Request) {
+	id, interviewID := subresourceParams(r, "interviews")
+	if err := storage.DeleteInterview(r.Context(), workspaceID(r), id, interviewID); err != nil {
+		respondWithInterviewError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), workspaceID(r), models.AuditInterviewDeleted, id, "")
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Interview deleted successfully",
+	})
+}
+
+// UpcomingInterviewsHandler returns the pending interviews in the next ?days=14 days
+// across the workspace, earliest first
+func UpcomingInterviewsHandler(w http.ResponseWriter, r *http.Request) {
+	days := defaultUpcomingDays
+	if param := r.URL.Query().Get("days"); param != "" {
+		n, err := strconv.Atoi(param)
+		if err != nil || n < 1 || n > maxUpcomingDays {
+			respondWithError(w, r, http.StatusBadRequest, "days must be between 1 and "+strconv.Itoa(maxUpcomingDays))
+			return
+		}
+		days = n
+	}
+
+	now := time.Now()
+	interviews, err := storage.ListUpcomingInterviews(r.Context(), workspaceID(r), now, now.AddDate(0, 0, days))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve interviews: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    interviews,
+	})
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ApplicationTracker/models"
+)
+
+const (
+	// commentBodyBytes caps the body of comment requests
+	commentBodyBytes = 16 << 10
+	// noteBodyBytes caps the body of note, interview, contact and company requests
+	noteBodyBytes = 64 << 10
+	// tokenBodyBytes caps the body of token requests
+	tokenBodyBytes = 4 << 10
+	// tagBodyBytes caps the body of tag rename and color requests
+	tagBodyBytes = 4 << 10
+	// fieldBodyBytes caps the body of custom field requests, which may list many options
+	fieldBodyBytes = 16 << 10
+)
+
+// Limits caps the size of request bodies and application fields
+type Limits struct {
+	MaxRequestBytes      int64
+	MaxTags              int
+	MaxTagLength         int
+	MaxFieldLength       int
+	MaxURLLength         int
+	MaxDescriptionLength int
+}
+
+// limits is set from the configuration by SetupRouter
+var limits Limits
+
+// limitBody rejects request bodies larger than maxBytes; handlers see the error when reading the body
+func limitBody(maxBytes int64, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		next(w, r)
+	}
+}
+
+// respondWithBodyError responds to a request body that could not be read or parsed,
+// with 413 if it was too large
+func respondWithBodyError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		respondWithError(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body must not exceed %d bytes", maxErr.Limit))
+		return
+	}
+	respondWithError(w, r, http.StatusBadRequest, message+": "+err.Error())
+}
+
+// validateApplicationRequest normalizes the tags of an application request and checks the
+// status and the fields against the configured limits, returning a message for the first
+// violation
+func validateApplicationRequest(req *ApplicationRequest) string {
+	checks := []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"Company", req.Company, limits.MaxFieldLength},
+		{"Position", req.Position, limits.MaxFieldLength},
+		{"URL", req.URL, limits.MaxURLLength},
+		{"Description", req.Description, limits.MaxDescriptionLength},
+	}
+	for _, check := range checks {
+		if len(check.value) > check.max {
+			return fmt.Sprintf("%s must not exceed %d characters", check.name, check.max)
+		}
+	}
+	if req.Status != "" && !models.IsValidStatus(req.Status) {
+		return "Invalid status value"
+	}
+
+	for _, pay := range []*models.Compensation{req.Compensation, req.Offer} {
+		if pay == nil {
+			continue
+		}
+		if err := pay.Normalize(); err != nil {
+			return "Invalid compensation: " + err.Error()
+		}
+	}
+	if message := validateLocation(req); message != "" {
+		return message
+	}
+
+	req.Tags = models.NormalizeTags(req.Tags)
+	if len(req.Tags) > limits.MaxTags {
+		return fmt.Sprintf("An application can have at most %d tags", limits.MaxTags)
+	}
+	for _, tag := range req.Tags {
+		if len(tag) > limits.MaxTagLength {
+			return fmt.Sprintf("Tags must not exceed %d characters", limits.MaxTagLength)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ApplicationTracker/models"
+)
+
+// TimeZoneRequest is the time zone requirement of an application request; a single
+// offset means exactly that offset, and neither clears the requirement
+type TimeZoneRequest struct {
+	MinOffset *float64 `json:"minOffset"`
+	MaxOffset *float64 `json:"maxOffset"`
+}
+
+// requirement returns the requirement the request describes, or nil if it clears it
+func (t TimeZoneRequest) requirement() *models.TimeZoneRequirement {
+	switch {
+	case t.MinOffset == nil && t.MaxOffset == nil:
+		return nil
+	case t.MinOffset == nil:
+		return &models.TimeZoneRequirement{MinOffset: *t.MaxOffset, MaxOffset: *t.MaxOffset}
+	case t.MaxOffset == nil:
+		return &models.TimeZoneRequirement{MinOffset: *t.MinOffset, MaxOffset: *t.MinOffset}
+	}
+	return &models.TimeZoneRequirement{MinOffset: *t.MinOffset, MaxOffset: *t.MaxOffset}
+}
+
+// parseOffset parses a UTC offset in hours, such as "-5" or "5.5"
+func parseOffset(name, value string) (*float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	offset, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a UTC offset in hours, such as -5 or 5.5", name)
+	}
+	return &offset, nil
+}
+
+// locationFromForm reads the location ("location.*" fields), work mode and time zone
+// requirement ("timeZone.*" fields) of an application form; each is left alone if the
+// form doesn't have its fields
+func locationFromForm(r *http.Request, req *ApplicationRequest) error {
+	if _, ok := r.Form["location.city"]; ok {
+		req.Location = &models.Location{
+			City:    r.FormValue("location.city"),
+			Region:  r.FormValue("location.region"),
+			Country: r.FormValue("location.country"),
+		}
+	}
+	if _, ok := r.Form["workMode"]; ok {
+		mode := r.FormValue("workMode")
+		req.WorkMode = &mode
+	}
+	if _, ok := r.Form["timeZone.minOffset"]; ok {
+		var err error
+		req.TimeZone = &TimeZoneRequest{}
+		if req.TimeZone.MinOffset, err = parseOffset("timeZone.minOffset", r.FormValue("timeZone.minOffset")); err != nil {
+			return err
+		}
+		if req.TimeZone.MaxOffset, err = parseOffset("timeZone.maxOffset", r.FormValue("timeZone.maxOffset")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateLocation normalizes the location, work mode and time zone of a request,
+// returning a message for the first problem
+func validateLocation(req *ApplicationRequest) string {
+	if req.Location != nil {
+		req.Location.Normalize()
+		for _, part := range []string{req.Location.City, req.Location.Region, req.Location.Country} {
+			if len(part) > limits.MaxFieldLength {
+				return fmt.Sprintf("Location must not exceed %d characters per part", limits.MaxFieldLength)
+			}
+		}
+	}
+	if req.WorkMode != nil {
+		*req.WorkMode = strings.ToLower(strings.TrimSpace(*req.WorkMode))
+		if *req.WorkMode != "" && !models.IsValidWorkMode(*req.WorkMode) {
+			return fmt.Sprintf("workMode must be %q, %q or %q", models.WorkModeRemote, models.WorkModeHybrid, models.WorkModeOnsite)
+		}
+	}
+	if req.TimeZone != nil {
+		if tz := req.TimeZone.requirement(); tz != nil {
+			if err := tz.Validate(); err != nil {
+				return "Invalid time zone: " + err.Error()
+			}
+		}
+	}
+	return ""
+}
+
+// applyLocation sets the location, work mode and time zone of a request on an
+// application; fields the request omits are kept
+func applyLocation(req ApplicationRequest, application *models.Application) {
+	if req.Location != nil {
+		application.Location = req.Location
+		if req.Location.IsZero() {
+			application.Location = nil
+		}
+	}
+	if req.WorkMode != nil {
+		application.WorkMode = *req.WorkMode
+	}
+	if req.TimeZone != nil {
+		application.TimeZone = req.TimeZone.requirement()
+	}
+}
+
+// locationFilter reads the workMode, location and utcOffset query parameters of a search
+func locationFilter(r *http.Request) (models.LocationFilter, error) {
+	query := r.URL.Query()
+	filter := models.LocationFilter{
+		WorkMode: strings.ToLower(strings.TrimSpace(query.Get("workMode"))),
+		Place:    strings.TrimSpace(query.Get("location")),
+	}
+	if filter.WorkMode != "" && !models.IsValidWorkMode(filter.WorkMode) {
+		return filter, fmt.Errorf("workMode must be %q, %q or %q", models.WorkModeRemote, models.WorkModeHybrid, models.WorkModeOnsite)
+	}
+	offset, err := parseOffset("utcOffset", query.Get("utcOffset"))
+	if err != nil {
+		return filter, err
+	}
+	filter.UTCOffset = offset
+	return filter, nil
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// NoteRequest is the structure for note create and update requests
+type NoteRequest struct {
+	Body string `json:"body"`
+}
+
+// decodeNoteRequest decodes and validates the body of a note request, writing an error
+// response if it is invalid
+func decodeNoteRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req NoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return "", false
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" || len(body) > models.MaxNoteLength {
+		respondWithError(w, r, http.StatusBadRequest, fmt.Sprintf("Note must be between 1 and %d characters", models.MaxNoteLength))
+		return "", false
+	}
+	return body, true
+}
+
+// ListNotesHandler returns the notes on an application, newest first
+func ListNotesHandler(w http.ResponseWriter, r *http.Request) {
+	id, _ := subresourceParams(r, "notes")
+	if !requireApplication(w, r, id) {
+		return
+	}
+
+	notes, err := storage.ListNotes(r.Context(), workspaceID(r), id)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve notes: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    notes,
+	})
+}
+
+// GetNoteHandler returns a note on an application
+func GetNoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, noteID := subresourceParams(r, "notes")
+	note, err := storage.GetNote(r.Context(), workspaceID(r), id, noteID)
+	if err != nil {
+		if err == storage.ErrNoteNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Note not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve note: "+err.Error())
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    note,
+	})
+}
+
+// CreateNoteHandler adds a note to an application
+func CreateNoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, _ := subresourceParams(r, "notes")
+	if !requireApplication(w, r, id) {
+		return
+	}
+	body, ok := decodeNoteRequest(w, r)
+	if !ok {
+		return
+	}
+
+	note := models.NewNote(workspaceID(r), id, auth.UserFromContext(r.Context()), body)
+	if err := storage.CreateNote(r.Context(), note); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to save note: "+err.Error())
+		return
+	}
+	auth.Audit(r.Context(), note.WorkspaceID, models.AuditNoteCreated, id, "")
+
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Message: "Note created successfully",
+		Data:    note,
+	})
+}
+
+// UpdateNoteHandler replaces the body of a note
+func UpdateNoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, noteID := subresourceParams(r, "notes")
+	body, ok := decodeNoteRequest(w, r)
+	if !ok {
+		return
+	}
+
+	note, err := storage.UpdateNote(r.Context(), workspaceID(r), id, noteID, body)
+	if err != nil {
+		if err == storage.ErrNoteNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Note not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to update note: "+err.Error())
+		}
+		return
+	}
+	auth.Audit(r.Context(), note.WorkspaceID, models.AuditNoteUpdated, id, "")
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Note updated successfully",
+		Data:    note,
+	})
+}
+
+// DeleteNoteHandler removes a note from an application
+func DeleteNoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, noteID := subresourceParams(r, "notes")
+	if err := storage.DeleteNote(r.Context(), workspaceID(r), id, noteID); err != nil {
+		if err == storage.ErrNoteNotFound {
+			respondWithError(w, r, http.StatusNotFound, "Note not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to delete note: "+err.Error())
+		}
+		return
+	}
+	auth.Audit(r.Context(), workspaceID(r), models.AuditNoteDeleted, id, "")
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Note deleted successfully",
+	})
+}
@@ -0,0 +1,348 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// ReminderRequest is the structure for reminder create and update requests; updates
+// keep the message and due time when they are omitted
+type ReminderRequest struct {
+	Message string    `json:"message"`
+	DueAt   time.Time `json:"dueAt"`
+	Done    *bool     `json:"done"`
+}
+
+// SnoozeRequest is the structure for requests snoozing a reminder, either until a time
+// or for a number of hours
+type SnoozeRequest struct {
+	Until *time.Time `json:"until"`
+	Hours int        `json:"hours"`
+}
+
+// ReminderRuleRequest is the structure for reminder rule create and update requests
+type ReminderRuleRequest struct {
+	Status  string `json:"status"`
+	Days    int    `json:"days"`
+	Message string `json:"message"`
+	Enabled *bool  `json:"enabled"`
+}
+
+// reminderParams extracts the application and reminder IDs from
+// /applications/{id}/reminders[/{reminderId}[/snooze]]
+func reminderParams(r *http.Request) (applicationID, reminderID string) {
+	applicationID, reminderID = subresourceParams(r, "reminders")
+	return applicationID, strings.TrimSuffix(reminderID, "/snooze")
+}
+
+// respondWithReminderError writes the response for a failed reminder lookup or change
+func respondWithReminderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case storage.ErrReminderNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Reminder not found")
+	case storage.ErrReminderRuleNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Reminder rule not found")
+	case storage.ErrTooManyReminderRules:
+		respondWithError(w, r, http.StatusConflict, fmt.Sprintf("A workspace can have at most %d reminder rules", storage.MaxReminderRules))
+	case storage.ErrNotificationNotFound:
+		respondWithError(w, r, http.StatusNotFound, "Notification not found")
+	default:
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to access reminders: "+err.Error())
+	}
+}
+
+// ListRemindersHandler returns the reminders on an application, soonest due first
+func ListRemindersHandler(w http.ResponseWriter, r *http.Request) {
+	id, _ := reminderParams(r)
+	if !requireApplication(w, r, id) {
+		return
+	}
+
+	reminders, err := storage.ListReminders(r.Context(), workspaceID(r), id)
+	if err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    reminders,
+	})
+}
+
+// CreateReminderHandler adds a reminder to an application
+func CreateReminderHandler(w http.ResponseWriter, r *http.Request) {
+	id, _ := reminderParams(r)
+	if !requireApplication(w, r, id) {
+		return
+	}
+	var req ReminderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	reminder, err := models.NewReminder(workspaceID(r), id, req.Message, req.DueAt)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := storage.CreateReminder(r.Context(), reminder); err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), reminder.WorkspaceID, models.AuditReminderCreated, id, "")
+
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Message: "Reminder created successfully",
+		Data:    reminder,
+	})
+}
+
+// UpdateReminderHandler changes the message, due time or done flag of a reminder
+func UpdateReminderHandler(w http.ResponseWriter, r *http.Request) {
+	id, reminderID := reminderParams(r)
+	var req ReminderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	reminder, err := storage.GetReminder(r.Context(), workspaceID(r), id, reminderID)
+	if err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+	if req.Message == "" {
+		req.Message = reminder.Message
+	}
+	if req.DueAt.IsZero() {
+		req.DueAt = reminder.DueAt
+	}
+	done := reminder.Done
+	if req.Done != nil {
+		done = *req.Done
+	}
+	if err := reminder.Update(req.Message, req.DueAt, done); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := storage.SaveReminder(r.Context(), reminder); err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), reminder.WorkspaceID, models.AuditReminderUpdated, id, reminder.State())
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Reminder updated successfully",
+		Data:    reminder,
+	})
+}
+
+// SnoozeReminderHandler postpones a reminder so it fires again later
+func SnoozeReminderHandler(w http.ResponseWriter, r *http.Request) {
+	id, reminderID := reminderParams(r)
+	var req SnoozeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+	now := time.Now()
+	var until time.Time
+	switch {
+	case req.Until != nil:
+		until = *req.Until
+	case req.Hours > 0 && req.Hours <= 24*models.MaxReminderRuleDays:
+		until = now.Add(time.Duration(req.Hours) * time.Hour)
+	default:
+		respondWithError(w, r, http.StatusBadRequest, "Either until or hours is required")
+		return
+	}
+
+	reminder, err := storage.GetReminder(r.Context(), workspaceID(r), id, reminderID)
+	if err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+	if err := reminder.Snooze(until, now); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := storage.SaveReminder(r.Context(), reminder); err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), reminder.WorkspaceID, models.AuditReminderUpdated, id, "snoozed")
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Reminder snoozed successfully",
+		Data:    reminder,
+	})
+}
+
+// DeleteReminderHandler removes a reminder from an application
+func DeleteReminderHandler(w http.ResponseWriter, r *http.Request) {
+	id, reminderID := reminderParams(r)
+	if err := storage.DeleteReminder(r.Context(), workspaceID(r), id, reminderID); err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+	auth.Audit(r.Context(), workspaceID(r), models.AuditReminderDeleted, id, "")
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Reminder deleted successfully",
+	})
+}
+
+// ListOpenRemindersHandler returns the reminders in the workspace that aren't done
+func ListOpenRemindersHandler(w http.ResponseWriter, r *http.Request) {
+	reminders, err := storage.ListOpenReminders(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    reminders,
+	})
+}
+
+// ListReminderRulesHandler returns the reminder rules of the workspace
+func ListReminderRulesHandler(w http.ResponseWriter, r *http.Request) {
+	rules, err := storage.ListReminderRules(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    rules,
+	})
+}
+
+// CreateReminderRuleHandler adds a reminder rule to the workspace
+func CreateReminderRuleHandler(w http.ResponseWriter, r *http.Request) {
+	var req ReminderRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	rule, err := models.NewReminderRule(workspaceID(r), req.Status, req.Days, req.Message)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if err := storage.CreateReminderRule(r.Context(), rule); err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Message: "Reminder rule created successfully",
+		Data:    rule,
+	})
+}
+
+// UpdateReminderRuleHandler replaces the settings of a reminder rule; omitting enabled
+// keeps it as it was
+func UpdateReminderRuleHandler(w http.ResponseWriter, r *http.Request) {
+	var req ReminderRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	rule, err := storage.GetReminderRule(r.Context(), workspaceID(r), strings.TrimPrefix(r.URL.Path, "/reminders/rules/"))
+	if err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+	enabled := rule.Enabled
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	if err := rule.Update(req.Status, req.Days, req.Message, enabled); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := storage.SaveReminderRule(r.Context(), rule); err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Reminder rule updated successfully",
+		Data:    rule,
+	})
+}
+
+// DeleteReminderRuleHandler removes a reminder rule from the workspace
+func DeleteReminderRuleHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/reminders/rules/")
+	if err := storage.DeleteReminderRule(r.Context(), workspaceID(r), id); err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Reminder rule deleted successfully",
+	})
+}
+
+// ListNotificationsHandler returns the notifications of the workspace, newest first,
+// with the number that are unread
+func ListNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	notifications, unread, err := storage.ListNotifications(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data: map[string]interface{}{
+			"notifications": notifications,
+			"unread":        unread,
+		},
+	})
+}
+
+// ReadNotificationsHandler marks one notification, or all of them, read
+func ReadNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	var err error
+	if r.URL.Path == "/notifications/read" {
+		err = storage.MarkAllNotificationsRead(r.Context(), workspaceID(r))
+	} else {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/notifications/"), "/read")
+		err = storage.MarkNotificationRead(r.Context(), workspaceID(r), id)
+	}
+	if err != nil {
+		respondWithReminderError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Notifications marked read",
+	})
+}
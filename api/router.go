@@ -1,42 +1,153 @@
 package api
 
 import (
-	"ApplicationTracker/storage"
-	"log"
+	"ApplicationTracker/auth"
+	"ApplicationTracker/config"
+	"ApplicationTracker/health"
+	"ApplicationTracker/metrics"
+	"ApplicationTracker/models"
+	"ApplicationTracker/ratelimit"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before writing it
+func (rec *statusRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
 // Middleware for logging requests
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		log.Printf("Started %s %s", r.Method, r.URL.Path)
-		next.ServeHTTP(w, r)
-		log.Printf("Completed %s %s in %v", r.Method, r.URL.Path, time.Since(start))
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		slog.InfoContext(r.Context(), "request completed",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration", time.Since(start),
+		)
 	})
 }
 
-// Middleware for CORS
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+// corsMiddleware lets the allowed origins call the API from a browser; "*" allows any origin
+func corsMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[origin] = true
+	}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+			if origin != "" && (allowed[origin] || allowed["*"]) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, X-Request-ID, X-Workspace-ID")
+				w.Header().Set("Access-Control-Expose-Headers", "Idempotent-Replayed, Retry-After, X-Request-ID")
+			}
+
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitMiddleware throttles API clients with a token bucket per API token, user or
+// IP address, answering 429 with a Retry-After header when a client runs out; health
+// checks are exempt so probes are never throttled
+func rateLimitMiddleware(limiter *ratelimit.Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/health") {
+			next.ServeHTTP(w, r)
 			return
 		}
 
+		allowed, wait := limiter.Allow(rateLimitKey(r), time.Now())
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			respondWithError(w, r, http.StatusTooManyRequests, "Rate limit exceeded, retry later")
+			return
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
+// rateLimitKey identifies the client of a request for rate limiting
+func rateLimitKey(r *http.Request) string {
+	if token := auth.TokenFromContext(r.Context()); token != nil {
+		return "token:" + token.ID
+	}
+	if user := auth.UserFromContext(r.Context()); user != nil {
+		return "user:" + user.ID
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// requireAuth rejects requests without an authenticated user
+func requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if auth.UserFromContext(r.Context()) == nil {
+			if isHtmxRequest(r) {
+				w.Header().Set("HX-Redirect", "/login")
+			}
+			respondWithError(w, r, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+		if auth.WorkspaceFromContext(r.Context()) == nil {
+			respondWithError(w, r, http.StatusForbidden, "Not a member of the requested workspace")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireScope rejects requests whose API token was not granted scope
+func requireScope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !auth.HasScope(r.Context(), scope) {
+			respondWithError(w, r, http.StatusForbidden, "Token is missing the required scope: "+scope)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireRole rejects requests whose role in the workspace does not include role
+func requireRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !auth.HasRole(r.Context(), role) {
+			respondWithError(w, r, http.StatusForbidden, "Your role in this workspace does not allow this action")
+			return
+		}
+		next(w, r)
+	}
+}
+
 // applicationHandler handles all application-related requests
 func applicationHandler(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Received API request: %s %s", r.Method, r.URL.Path)
 	// Extract the ID from the path if present
 	path := strings.TrimPrefix(r.URL.Path, "/applications")
 
@@ -44,73 +155,494 @@ func applicationHandler(w http.ResponseWriter, r *http.Request) {
 	switch {
 	case r.Method == http.MethodGet && path == "":
 		// GET /api/applications - Get all applications
-		log.Printf("Routing to GetAllApplicationsHandler")
-		GetAllApplicationsHandler(w, r)
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, GetAllApplicationsHandler))(w, r)
 
 	case r.Method == http.MethodGet && path == "/search":
 		// GET /api/applications/search - Search applications
-		log.Printf("Routing to SearchApplicationsHandler")
-		SearchApplicationsHandler(w, r)
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, SearchApplicationsHandler))(w, r)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/comments"):
+		// GET /api/applications/{id}/comments - List comments on an application
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListCommentsHandler))(w, r)
+
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/comments"):
+		// POST /api/applications/{id}/comments - Comment on an application
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleCommenter, limitBody(commentBodyBytes, CreateCommentHandler)))(w, r)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/notes"):
+		// GET /api/applications/{id}/notes - List notes on an application
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListNotesHandler))(w, r)
+
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/notes"):
+		// POST /api/applications/{id}/notes - Add a note to an application
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(noteBodyBytes, CreateNoteHandler)))(w, r)
+
+	case r.Method == http.MethodGet && strings.Contains(path, "/notes/"):
+		// GET /api/applications/{id}/notes/{noteId} - Get a note
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, GetNoteHandler))(w, r)
+
+	case r.Method == http.MethodPut && strings.Contains(path, "/notes/"):
+		// PUT /api/applications/{id}/notes/{noteId} - Edit a note
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(noteBodyBytes, UpdateNoteHandler)))(w, r)
+
+	case r.Method == http.MethodDelete && strings.Contains(path, "/notes/"):
+		// DELETE /api/applications/{id}/notes/{noteId} - Delete a note
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, DeleteNoteHandler))(w, r)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/interviews"):
+		// GET /api/applications/{id}/interviews - List interviews of an application
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListInterviewsHandler))(w, r)
+
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/interviews"):
+		// POST /api/applications/{id}/interviews - Schedule an interview
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(noteBodyBytes, CreateInterviewHandler)))(w, r)
+
+	case r.Method == http.MethodGet && strings.Contains(path, "/interviews/"):
+		// GET /api/applications/{id}/interviews/{interviewId} - Get an interview
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, GetInterviewHandler))(w, r)
+
+	case r.Method == http.MethodPut && strings.Contains(path, "/interviews/"):
+		// PUT /api/applications/{id}/interviews/{interviewId} - Update an interview
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(noteBodyBytes, UpdateInterviewHandler)))(w, r)
+
+	case r.Method == http.MethodDelete && strings.Contains(path, "/interviews/"):
+		// DELETE /api/applications/{id}/interviews/{interviewId} - Delete an interview
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, DeleteInterviewHandler))(w, r)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/contacts"):
+		// GET /api/applications/{id}/contacts - List contacts linked to an application
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListApplicationContactsHandler))(w, r)
+
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/contacts"):
+		// POST /api/applications/{id}/contacts - Link a contact to an application
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(tagBodyBytes, LinkContactHandler)))(w, r)
+
+	case r.Method == http.MethodDelete && strings.Contains(path, "/contacts/"):
+		// DELETE /api/applications/{id}/contacts/{contactId} - Unlink a contact from an application
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, UnlinkContactHandler))(w, r)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/reminders"):
+		// GET /api/applications/{id}/reminders - List reminders on an application
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListRemindersHandler))(w, r)
+
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/reminders"):
+		// POST /api/applications/{id}/reminders - Add a reminder to an application
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(noteBodyBytes, CreateReminderHandler)))(w, r)
+
+	case r.Method == http.MethodPost && strings.Contains(path, "/reminders/") && strings.HasSuffix(path, "/snooze"):
+		// POST /api/applications/{id}/reminders/{reminderId}/snooze - Snooze a reminder
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(tagBodyBytes, SnoozeReminderHandler)))(w, r)
+
+	case r.Method == http.MethodPut && strings.Contains(path, "/reminders/"):
+		// PUT /api/applications/{id}/reminders/{reminderId} - Update a reminder or mark it done
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(noteBodyBytes, UpdateReminderHandler)))(w, r)
+
+	case r.Method == http.MethodDelete && strings.Contains(path, "/reminders/"):
+		// DELETE /api/applications/{id}/reminders/{reminderId} - Delete a reminder
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, DeleteReminderHandler))(w, r)
+
+	case r.Method == http.MethodPost && path == "/bulk":
+		// POST /api/applications/bulk - Apply several operations in one write
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(limits.MaxRequestBytes, BulkApplicationsHandler)))(w, r)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/duplicates"):
+		// GET /api/applications/{id}/duplicates - List likely duplicates of an application
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, FindDuplicatesHandler))(w, r)
+
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/merge"):
+		// POST /api/applications/{id}/merge - Merge a duplicate into an application
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(limits.MaxRequestBytes, MergeApplicationHandler)))(w, r)
 
 	case r.Method == http.MethodGet && path != "":
 		// GET /api/applications/{id} - Get application by ID
-		log.Printf("Routing to GetApplicationHandler with path: %s", path)
-		GetApplicationHandler(w, r)
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, GetApplicationHandler))(w, r)
 
 	case r.Method == http.MethodPost && path == "":
 		// POST /api/applications - Create new application
-		log.Printf("Routing to CreateApplicationHandler")
-		CreateApplicationHandler(w, r)
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(limits.MaxRequestBytes, idempotent(CreateApplicationHandler))))(w, r)
 
 	case r.Method == http.MethodPut && strings.Contains(path, "/status"):
 		// PUT /api/applications/{id}/status - Update application status
-		log.Printf("Routing to UpdateApplicationStatusHandler with path: %s", path)
-		UpdateApplicationStatusHandler(w, r)
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(limits.MaxRequestBytes, UpdateApplicationStatusHandler)))(w, r)
 
 	case r.Method == http.MethodPut && path != "":
 		// PUT /api/applications/{id} - Update application
-		log.Printf("Routing to UpdateApplicationHandler with path: %s", path)
-		UpdateApplicationHandler(w, r)
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(limits.MaxRequestBytes, UpdateApplicationHandler)))(w, r)
 
 	case r.Method == http.MethodDelete && path != "":
 		// DELETE /api/applications/{id} - Delete application
-		log.Printf("Routing to DeleteApplicationHandler with path: %s", path)
-		DeleteApplicationHandler(w, r)
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, DeleteApplicationHandler))(w, r)
 
 	default:
 		// Method not allowed or route not found
-		log.Printf("ERROR: Method not allowed or route not found: %s %s", r.Method, r.URL.Path)
+		slog.WarnContext(r.Context(), "method not allowed or route not found", "method", r.Method, "path", r.URL.Path)
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		w.Write([]byte("Method not allowed or route not found"))
 	}
 }
 
-// healthCheckHandler handles health check requests
+// tokenHandler handles API token management requests; managing tokens requires the admin scope
+func tokenHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/tokens")
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		// GET /api/tokens - List the current user's tokens
+		requireScope(models.ScopeAdmin, ListTokensHandler)(w, r)
+
+	case r.Method == http.MethodPost && path == "":
+		// POST /api/tokens - Create a token
+		requireScope(models.ScopeAdmin, limitBody(tokenBodyBytes, CreateTokenHandler))(w, r)
+
+	case r.Method == http.MethodDelete && path != "":
+		// DELETE /api/tokens/{id} - Revoke a token
+		requireScope(models.ScopeAdmin, RevokeTokenHandler)(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// tagHandler handles tag management requests
+func tagHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/tags")
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		// GET /api/tags - List tags with usage counts and colors
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListTagsHandler))(w, r)
+
+	case r.Method == http.MethodPost && path == "/rename":
+		// POST /api/tags/rename - Rename a tag on every application
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(tagBodyBytes, RenameTagHandler)))(w, r)
+
+	case r.Method == http.MethodPost && path == "/merge":
+		// POST /api/tags/merge - Merge several tags into one
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(limits.MaxRequestBytes, MergeTagsHandler)))(w, r)
+
+	case r.Method == http.MethodPut && path == "/color":
+		// PUT /api/tags/color - Choose the color of a tag
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(tagBodyBytes, SetTagColorHandler)))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// contactHandler handles contact requests
+func contactHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/contacts")
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		// GET /api/contacts - List contacts, optionally searching with ?q=
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListContactsHandler))(w, r)
+
+	case r.Method == http.MethodPost && path == "":
+		// POST /api/contacts - Create a contact
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(noteBodyBytes, CreateContactHandler)))(w, r)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/applications"):
+		// GET /api/contacts/{id}/applications - List the applications a contact is linked to
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListContactApplicationsHandler))(w, r)
+
+	case r.Method == http.MethodGet && path != "":
+		// GET /api/contacts/{id} - Get a contact
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, GetContactHandler))(w, r)
+
+	case r.Method == http.MethodPut && path != "":
+		// PUT /api/contacts/{id} - Update a contact
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(noteBodyBytes, UpdateContactHandler)))(w, r)
+
+	case r.Method == http.MethodDelete && path != "":
+		// DELETE /api/contacts/{id} - Delete a contact
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, DeleteContactHandler))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// companyHandler handles company requests
+func companyHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/companies")
+	path = strings.TrimPrefix(path, "/")
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		// GET /api/companies - List companies with their application history
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListCompaniesHandler))(w, r)
+
+	case r.Method == http.MethodPost && path == "":
+		// POST /api/companies - Create a company
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(noteBodyBytes, CreateCompanyHandler)))(w, r)
+
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/applications"):
+		// GET /api/companies/{id}/applications - List the applications to a company
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListCompanyApplicationsHandler))(w, r)
+
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/merge"):
+		// POST /api/companies/{id}/merge - Merge another company into this one
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(tagBodyBytes, MergeCompanyHandler)))(w, r)
+
+	case r.Method == http.MethodGet && path != "":
+		// GET /api/companies/{id} - Get a company with its application history
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, GetCompanyHandler))(w, r)
+
+	case r.Method == http.MethodPut && path != "":
+		// PUT /api/companies/{id} - Update a company
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(noteBodyBytes, UpdateCompanyHandler)))(w, r)
+
+	case r.Method == http.MethodDelete && path != "":
+		// DELETE /api/companies/{id} - Delete a company without applications
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, DeleteCompanyHandler))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// reminderHandler handles requests for reminders across applications and reminder rules
+func reminderHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/reminders")
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		// GET /api/reminders - List reminders that aren't done
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListOpenRemindersHandler))(w, r)
+
+	case r.Method == http.MethodGet && path == "/rules":
+		// GET /api/reminders/rules - List reminder rules
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListReminderRulesHandler))(w, r)
+
+	case r.Method == http.MethodPost && path == "/rules":
+		// POST /api/reminders/rules - Create a reminder rule
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(tagBodyBytes, CreateReminderRuleHandler)))(w, r)
+
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "/rules/"):
+		// PUT /api/reminders/rules/{id} - Update a reminder rule
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(tagBodyBytes, UpdateReminderRuleHandler)))(w, r)
+
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/rules/"):
+		// DELETE /api/reminders/rules/{id} - Delete a reminder rule
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, DeleteReminderRuleHandler))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// staleHandler handles requests for stale applications and the settings deciding them
+func staleHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/stale")
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		// GET /api/stale - List applications that need attention
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListStaleApplicationsHandler))(w, r)
+
+	case r.Method == http.MethodGet && path == "/settings":
+		// GET /api/stale/settings - Get the stale thresholds
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, GetStaleSettingsHandler))(w, r)
+
+	case r.Method == http.MethodPut && path == "/settings":
+		// PUT /api/stale/settings - Replace the stale thresholds
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(tagBodyBytes, UpdateStaleSettingsHandler)))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// notificationHandler handles notification requests
+func notificationHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/notifications")
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		// GET /api/notifications - List notifications with the unread count
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListNotificationsHandler))(w, r)
+
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/read"):
+		// POST /api/notifications/read - Mark all notifications read
+		// POST /api/notifications/{id}/read - Mark a notification read
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleViewer, ReadNotificationsHandler))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// offerHandler handles offer comparison requests
+func offerHandler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/offers":
+		// GET /api/offers - Compare the offers on accepted and in-progress applications
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, CompareOffersHandler))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// interviewHandler handles requests for interviews across applications
+func interviewHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/interviews")
+
+	switch {
+	case r.Method == http.MethodGet && path == "/upcoming":
+		// GET /api/interviews/upcoming - List pending interviews in the coming days
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, UpcomingInterviewsHandler))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// autoTagHandler handles auto-tag rule requests
+func autoTagHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/autotag")
+
+	switch {
+	case r.Method == http.MethodGet && path == "/rules":
+		// GET /api/autotag/rules - List auto-tag rules
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListAutoTagRulesHandler))(w, r)
+
+	case r.Method == http.MethodPost && path == "/rules":
+		// POST /api/autotag/rules - Create an auto-tag rule
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(tagBodyBytes, CreateAutoTagRuleHandler)))(w, r)
+
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/rules/"):
+		// DELETE /api/autotag/rules/{id} - Delete an auto-tag rule
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, DeleteAutoTagRuleHandler))(w, r)
+
+	case r.Method == http.MethodGet && path == "/preview":
+		// GET /api/autotag/preview - Show the tags the rules would add to existing applications
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, PreviewAutoTagsHandler))(w, r)
+
+	case r.Method == http.MethodPost && path == "/apply":
+		// POST /api/autotag/apply - Add the tags the rules match to existing applications
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, ApplyAutoTagsHandler))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// fieldHandler handles custom field requests
+func fieldHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/fields")
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		// GET /api/fields - List custom fields
+		requireScope(models.ScopeApplicationsRead, requireRole(models.RoleViewer, ListFieldDefinitionsHandler))(w, r)
+
+	case r.Method == http.MethodPost && path == "":
+		// POST /api/fields - Create a custom field
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, limitBody(fieldBodyBytes, CreateFieldDefinitionHandler)))(w, r)
+
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/"):
+		// DELETE /api/fields/{id} - Delete a custom field and its values
+		requireScope(models.ScopeApplicationsWrite, requireRole(models.RoleEditor, DeleteFieldDefinitionHandler))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// workspaceHandler handles workspace requests
+func workspaceHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/workspaces")
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		// GET /api/workspaces - List the workspaces the user can act on
+		requireScope(models.ScopeApplicationsRead, ListWorkspacesHandler)(w, r)
+
+	case r.Method == http.MethodGet && path == "/current/audit":
+		// GET /api/workspaces/current/audit - List recent activity in the workspace
+		requireScope(models.ScopeAdmin, requireRole(models.RoleOwner, ListAuditEventsHandler))(w, r)
+
+	default:
+		respondWithError(w, r, http.StatusMethodNotAllowed, "Method not allowed or route not found")
+	}
+}
+
+// healthCheckHandler handles liveness requests; it only confirms the process is serving HTTP
 func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Health check request received")
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"status":"ok"}`))
-	log.Printf("Health check response sent: status OK")
+}
+
+// readinessHandler runs the readiness checks and returns 503 if any of them fail
+func readinessHandler(checks []health.Check) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := health.Run(r.Context(), checks)
+
+		code := http.StatusOK
+		if !report.Healthy() {
+			code = http.StatusServiceUnavailable
+			for _, check := range report.Checks {
+				if check.Status == health.StatusFail {
+					slog.WarnContext(r.Context(), "readiness check failed", "check", check.Name, "error", check.Message)
+				}
+			}
+		}
+		respondWithJSON(w, code, report)
+	}
 }
 
 // SetupRouter initializes and returns the HTTP router
-func SetupRouter() http.Handler {
-	// Initialize storage
-	if err := storage.Initialize(); err != nil {
-		log.Fatalf("Failed to initialize storage: %v", err)
+func SetupRouter(cfg config.Config) http.Handler {
+	limits = Limits{
+		MaxRequestBytes:      cfg.MaxRequestBytes,
+		MaxTags:              cfg.MaxTags,
+		MaxTagLength:         cfg.MaxTagLength,
+		MaxFieldLength:       cfg.MaxFieldLength,
+		MaxURLLength:         cfg.MaxURLLength,
+		MaxDescriptionLength: cfg.MaxDescriptionLength,
 	}
+	idempotencyTTL = cfg.IdempotencyTTL
 
 	// Create a new ServeMux
 	mux := http.NewServeMux()
 
 	// Register routes
-	mux.HandleFunc("/applications", applicationHandler)
-	mux.HandleFunc("/applications/", applicationHandler)
+	mux.HandleFunc("/applications", requireAuth(applicationHandler))
+	mux.HandleFunc("/applications/", requireAuth(applicationHandler))
+	mux.HandleFunc("/tokens", requireAuth(tokenHandler))
+	mux.HandleFunc("/tokens/", requireAuth(tokenHandler))
+	mux.HandleFunc("/tags", requireAuth(tagHandler))
+	mux.HandleFunc("/tags/", requireAuth(tagHandler))
+	mux.HandleFunc("/autotag/", requireAuth(autoTagHandler))
+	mux.HandleFunc("/fields", requireAuth(fieldHandler))
+	mux.HandleFunc("/fields/", requireAuth(fieldHandler))
+	mux.HandleFunc("/interviews/", requireAuth(interviewHandler))
+	mux.HandleFunc("/contacts", requireAuth(contactHandler))
+	mux.HandleFunc("/contacts/", requireAuth(contactHandler))
+	mux.HandleFunc("/reminders", requireAuth(reminderHandler))
+	mux.HandleFunc("/reminders/", requireAuth(reminderHandler))
+	mux.HandleFunc("/offers", requireAuth(offerHandler))
+	mux.HandleFunc("/stale", requireAuth(staleHandler))
+	mux.HandleFunc("/stale/", requireAuth(staleHandler))
+	mux.HandleFunc("/notifications", requireAuth(notificationHandler))
+	mux.HandleFunc("/notifications/", requireAuth(notificationHandler))
+	mux.HandleFunc("/companies", requireAuth(companyHandler))
+	mux.HandleFunc("/companies/", requireAuth(companyHandler))
+	mux.HandleFunc("/workspaces", requireAuth(workspaceHandler))
+	mux.HandleFunc("/workspaces/", requireAuth(workspaceHandler))
 	mux.HandleFunc("/health", healthCheckHandler)
+	mux.HandleFunc("/health/live", healthCheckHandler)
+	mux.HandleFunc("/health/ready", readinessHandler(health.ReadinessChecks(uint64(cfg.MinFreeDiskMB)<<20)))
 
 	// Add middleware
-	handler := loggingMiddleware(corsMiddleware(mux))
+	var handler http.Handler = metrics.Routes("/api", mux)
+	if cfg.RateLimitRPS > 0 {
+		handler = rateLimitMiddleware(ratelimit.New(cfg.RateLimitRPS, cfg.RateLimitBurst), handler)
+	}
+	handler = loggingMiddleware(corsMiddleware(cfg.CORSAllowedOrigins)(handler))
 
 	return handler
 }
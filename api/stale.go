@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// StaleSettingsRequest is the structure for requests replacing the stale settings of
+// a workspace; statuses missing from days never become stale
+type StaleSettingsRequest struct {
+	Days           map[string]int `json:"days"`
+	GhostAfterDays int            `json:"ghostAfterDays"`
+}
+
+// ListStaleApplicationsHandler returns the applications that need attention because
+// they haven't been updated for a while, least recently updated first
+func ListStaleApplicationsHandler(w http.ResponseWriter, r *http.Request) {
+	applications, err := storage.ListStaleApplications(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve applications: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    applications,
+	})
+}
+
+// GetStaleSettingsHandler returns the stale settings of the workspace
+func GetStaleSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	settings, err := storage.GetStaleSettings(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve stale settings: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    settings,
+	})
+}
+
+// UpdateStaleSettingsHandler replaces the stale settings of the workspace
+func UpdateStaleSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	var req StaleSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+
+	settings := &models.StaleSettings{
+		WorkspaceID:    workspaceID(r),
+		Days:           req.Days,
+		GhostAfterDays: req.GhostAfterDays,
+	}
+	if settings.Days == nil {
+		settings.Days = map[string]int{}
+	}
+	if err := settings.Validate(); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := storage.SaveStaleSettings(r.Context(), settings); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to save stale settings: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Stale settings updated successfully",
+		Data:    settings,
+	})
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// RenameTagRequest is the structure for tag rename requests
+type RenameTagRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// MergeTagsRequest is the structure for tag merge requests
+type MergeTagsRequest struct {
+	Tags []string `json:"tags"`
+	Into string   `json:"into"`
+}
+
+// TagColorRequest is the structure for tag color requests
+type TagColorRequest struct {
+	Tag   string `json:"tag"`
+	Color string `json:"color"`
+}
+
+// decodeTagRequest reads a tag request from a JSON body, or from the form for HTMX requests
+func decodeTagRequest(w http.ResponseWriter, r *http.Request, v interface{}, fromForm func()) bool {
+	if isHtmxRequest(r) {
+		if err := r.ParseForm(); err != nil {
+			respondWithBodyError(w, r, "Invalid form data", err)
+			return false
+		}
+		fromForm()
+		return true
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return false
+	}
+	return true
+}
+
+// validateTagName checks a normalized tag name, returning a message if it is invalid
+func validateTagName(name string) string {
+	if name == "" {
+		return "Tag name is required"
+	}
+	if len(name) > limits.MaxTagLength {
+		return fmt.Sprintf("Tags must not exceed %d characters", limits.MaxTagLength)
+	}
+	return ""
+}
+
+// respondTagsChanged answers a successful tag change, asking HTMX pages to reload their tags
+func respondTagsChanged(w http.ResponseWriter, r *http.Request, message string, changed int) {
+	if isHtmxRequest(r) {
+		w.Header().Set("HX-Trigger", "tagsChanged")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: message,
+		Data:    map[string]int{"applicationsChanged": changed},
+	})
+}
+
+// ListTagsHandler returns the tags used in the workspace with usage counts and colors
+func ListTagsHandler(w http.ResponseWriter, r *http.Request) {
+	tags, err := storage.ListTags(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve tags: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    tags,
+	})
+}
+
+// RenameTagHandler renames a tag on every application in the workspace; renaming onto
+// an existing tag is refused so tags are only combined by an explicit merge
+func RenameTagHandler(w http.ResponseWriter, r *http.Request) {
+	var req RenameTagRequest
+	if !decodeTagRequest(w, r, &req, func() {
+		req.From, req.To = r.FormValue("from"), r.FormValue("to")
+	}) {
+		return
+	}
+
+	from, to := models.NormalizeTag(req.From), models.NormalizeTag(req.To)
+	if message := validateTagName(to); message != "" {
+		respondWithError(w, r, http.StatusBadRequest, message)
+		return
+	}
+
+	tags, err := storage.ListTags(r.Context(), workspaceID(r))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve tags: "+err.Error())
+		return
+	}
+	exists := func(name string) bool {
+		return slices.ContainsFunc(tags, func(t models.Tag) bool { return t.Name == name })
+	}
+	if !exists(from) {
+		respondWithError(w, r, http.StatusNotFound, "Tag not found")
+		return
+	}
+	if from != to && exists(to) {
+		respondWithError(w, r, http.StatusConflict, fmt.Sprintf("A tag named %q already exists; merge the tags instead", to))
+		return
+	}
+
+	changed, err := storage.ReplaceTags(r.Context(), workspaceID(r), []string{from}, to)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to rename tag: "+err.Error())
+		return
+	}
+	auth.Audit(r.Context(), workspaceID(r), models.AuditTagRenamed, to, "from "+from)
+
+	respondTagsChanged(w, r, "Tag renamed successfully", changed)
+}
+
+// MergeTagsHandler replaces several tags with one on every application in the workspace
+func MergeTagsHandler(w http.ResponseWriter, r *http.Request) {
+	var req MergeTagsRequest
+	if !decodeTagRequest(w, r, &req, func() {
+		req.Tags, req.Into = r.Form["tags"], r.FormValue("into")
+	}) {
+		return
+	}
+
+	into := models.NormalizeTag(req.Into)
+	if message := validateTagName(into); message != "" {
+		respondWithError(w, r, http.StatusBadRequest, message)
+		return
+	}
+	sources := models.NormalizeTags(req.Tags)
+	if len(sources) == 0 {
+		respondWithError(w, r, http.StatusBadRequest, "At least one tag to merge is required")
+		return
+	}
+
+	changed, err := storage.ReplaceTags(r.Context(), workspaceID(r), sources, into)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to merge tags: "+err.Error())
+		return
+	}
+	auth.Audit(r.Context(), workspaceID(r), models.AuditTagsMerged, into, "from "+strings.Join(sources, ", "))
+
+	respondTagsChanged(w, r, "Tags merged successfully", changed)
+}
+
+// SetTagColorHandler chooses the color a tag is shown in
+func SetTagColorHandler(w http.ResponseWriter, r *http.Request) {
+	var req TagColorRequest
+	if !decodeTagRequest(w, r, &req, func() {
+		req.Tag, req.Color = r.FormValue("tag"), r.FormValue("color")
+	}) {
+		return
+	}
+
+	tag := models.NormalizeTag(req.Tag)
+	if message := validateTagName(tag); message != "" {
+		respondWithError(w, r, http.StatusBadRequest, message)
+		return
+	}
+	if !models.IsValidTagColor(req.Color) {
+		respondWithError(w, r, http.StatusBadRequest, "Color must be one of: "+strings.Join(models.TagColors, ", "))
+		return
+	}
+
+	if err := storage.SetTagColor(r.Context(), workspaceID(r), tag, req.Color); err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to set tag color: "+err.Error())
+		return
+	}
+
+	respondTagsChanged(w, r, "Tag color updated successfully", 0)
+}
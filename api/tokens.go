@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// TokenRequest is the structure for API token creation requests
+type TokenRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expiresInDays,omitempty"`
+}
+
+// TokenResponse is returned when a token is created; the secret is only shown once
+type TokenResponse struct {
+	Token string `json:"token"`
+	*models.APIToken
+}
+
+// ListTokensHandler returns the current user's API tokens without their secrets
+func ListTokensHandler(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+
+	tokens, err := storage.ListTokens(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve tokens: "+err.Error())
+		return
+	}
+	for i := range tokens {
+		tokens[i].TokenHash = ""
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    tokens,
+	})
+}
+
+// CreateTokenHandler issues a new API token for the current user
+func CreateTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var req TokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithBodyError(w, r, "Invalid request payload", err)
+		return
+	}
+	if req.ExpiresInDays < 0 {
+		respondWithError(w, r, http.StatusBadRequest, "expiresInDays must not be negative")
+		return
+	}
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	secret, token, err := auth.IssueToken(r.Context(), auth.UserFromContext(r.Context()), req.Name, req.Scopes, ttl)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "Failed to create token: "+err.Error())
+		return
+	}
+	token.TokenHash = ""
+
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Message: "Token created successfully; store it now, it will not be shown again",
+		Data:    TokenResponse{Token: secret, APIToken: token},
+	})
+}
+
+// RevokeTokenHandler revokes one of the current user's API tokens
+func RevokeTokenHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/tokens/")
+	if id == "" {
+		respondWithError(w, r, http.StatusBadRequest, "Token ID is required")
+		return
+	}
+
+	token, err := storage.RevokeToken(r.Context(), auth.UserFromContext(r.Context()).ID, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			respondWithError(w, r, http.StatusNotFound, "Token not found")
+		} else {
+			respondWithError(w, r, http.StatusInternalServerError, "Failed to revoke token: "+err.Error())
+		}
+		return
+	}
+	token.TokenHash = ""
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Message: "Token revoked successfully",
+		Data:    token,
+	})
+}
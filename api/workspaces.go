@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/storage"
+)
+
+// auditPageSize is the number of audit events returned by the API
+const auditPageSize = 100
+
+// ListWorkspacesHandler returns the workspaces the current user can select with the X-Workspace-ID header
+func ListWorkspacesHandler(w http.ResponseWriter, r *http.Request) {
+	workspaces, err := auth.ListWorkspaces(r.Context(), auth.UserFromContext(r.Context()))
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve workspaces: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    workspaces,
+	})
+}
+
+// ListAuditEventsHandler returns the most recent activity in the current workspace
+func ListAuditEventsHandler(w http.ResponseWriter, r *http.Request) {
+	events, err := storage.ListAuditEvents(r.Context(), workspaceID(r), auditPageSize)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, "Failed to retrieve audit events: "+err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{
+		Success: true,
+		Data:    events,
+	})
+}
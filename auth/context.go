@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+
+	"ApplicationTracker/models"
+)
+
+type contextKey int
+
+const (
+	userKey contextKey = iota
+	tokenKey
+	workspaceKey
+)
+
+// WithUser returns a copy of ctx carrying the authenticated user
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFromContext returns the authenticated user, or nil for anonymous requests
+func UserFromContext(ctx context.Context) *models.User {
+	user, _ := ctx.Value(userKey).(*models.User)
+	return user
+}
+
+// WithToken returns a copy of ctx carrying the API token used to authenticate
+func WithToken(ctx context.Context, token *models.APIToken) context.Context {
+	return context.WithValue(ctx, tokenKey, token)
+}
+
+// TokenFromContext returns the API token used to authenticate, or nil for browser sessions
+func TokenFromContext(ctx context.Context) *models.APIToken {
+	token, _ := ctx.Value(tokenKey).(*models.APIToken)
+	return token
+}
+
+// WithWorkspace returns a copy of ctx carrying the workspace the request acts on
+func WithWorkspace(ctx context.Context, workspace *models.Workspace) context.Context {
+	return context.WithValue(ctx, workspaceKey, workspace)
+}
+
+// WorkspaceFromContext returns the workspace the request acts on, or nil if there is none
+func WorkspaceFromContext(ctx context.Context) *models.Workspace {
+	workspace, _ := ctx.Value(workspaceKey).(*models.Workspace)
+	return workspace
+}
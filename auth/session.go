@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// SessionCookieName is the name of the cookie holding the session token
+const SessionCookieName = "session"
+
+// ErrInvalidCredentials is returned when a username or password is wrong
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// Options configures sessions and account creation
+type Options struct {
+	// SessionTTL is how long a login stays valid
+	SessionTTL time.Duration
+	// CookieSecure is "auto" (secure over HTTPS only), "true" or "false"
+	CookieSecure string
+	// AllowSignup lets anyone register; otherwise only the first account can be created
+	AllowSignup bool
+	// MaxFormBytes caps the form bodies CSRF reads the token from
+	MaxFormBytes int64
+}
+
+var options = Options{
+	SessionTTL:   7 * 24 * time.Hour,
+	CookieSecure: "auto",
+	MaxFormBytes: 1 << 20,
+}
+
+// Configure sets the session and signup options
+func Configure(opts Options) {
+	options = opts
+}
+
+// NewToken returns a random URL-safe token
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the SHA-256 hex digest under which a token is stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignupAllowed reports whether a new account may be registered
+func SignupAllowed(ctx context.Context) (bool, error) {
+	if options.AllowSignup {
+		return true, nil
+	}
+	count, err := storage.CountUsers(ctx)
+	if err != nil {
+		return false, err
+	}
+	return count == 0, nil
+}
+
+// Register validates the credentials and creates a new user
+func Register(ctx context.Context, username, password string) (*models.User, error) {
+	if err := ValidateUsername(username); err != nil {
+		return nil, err
+	}
+	if err := ValidatePassword(password); err != nil {
+		return nil, err
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user := models.NewUser(username, hash)
+	if err := storage.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ValidateUsername checks that a username is 3-32 letters, digits, '.', '-' or '_'
+func ValidateUsername(username string) error {
+	username = models.NormalizeUsername(username)
+	if len(username) < 3 || len(username) > 32 {
+		return errors.New("username must be between 3 and 32 characters")
+	}
+	for _, c := range username {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.ContainsRune(".-_", c)) {
+			return errors.New("username may only contain letters, digits, '.', '-' and '_'")
+		}
+	}
+	return nil
+}
+
+// Login checks the credentials and returns the matching user
+func Login(ctx context.Context, username, password string) (*models.User, error) {
+	user, err := storage.GetUserByUsername(ctx, username)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		// Hash anyway so unknown usernames can't be detected by timing
+		CheckPassword(dummyHash, password)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !CheckPassword(user.PasswordHash, password) {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// StartSession creates a session for the user and sets the session cookie
+func StartSession(w http.ResponseWriter, r *http.Request, user *models.User) error {
+	token, err := NewToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		TokenHash: HashToken(token),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(options.SessionTTL),
+	}
+	if err := storage.SaveSession(r.Context(), session); err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		MaxAge:   int(options.SessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   secureCookie(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	slog.InfoContext(r.Context(), "user logged in", "userId", user.ID)
+	return nil
+}
+
+// EndSession deletes the current session and clears the session cookie
+func EndSession(w http.ResponseWriter, r *http.Request) error {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   secureCookie(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	cookie, err := r.Cookie(SessionCookieName)
+	if err != nil || cookie.Value == "" {
+		return nil
+	}
+	return storage.DeleteSession(r.Context(), HashToken(cookie.Value))
+}
+
+// secureCookie decides whether the session cookie gets the Secure attribute
+func secureCookie(r *http.Request) bool {
+	switch options.CookieSecure {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// Authenticate loads the user for the bearer token or session cookie, if any, and the
+// workspace they act on into the request context. It never rejects a request; the api
+// and ui routers decide which routes require a user.
+func Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if secret, ok := bearerToken(r); ok {
+			token, user, err := tokenUser(r, secret)
+			if err != nil {
+				if !errors.Is(err, ErrInvalidToken) {
+					slog.ErrorContext(r.Context(), "failed to load API token", "error", err)
+				}
+				slog.WarnContext(r.Context(), "rejected API token")
+			} else {
+				ctx := WithToken(WithUser(r.Context(), user), token)
+				r = r.WithContext(withRequestWorkspace(r.WithContext(ctx), user))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if user := sessionUser(r); user != nil {
+			r = r.WithContext(WithUser(r.Context(), user))
+			r = r.WithContext(withRequestWorkspace(r, user))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sessionUser returns the user owning the request's session cookie
+func sessionUser(r *http.Request) *models.User {
+	cookie, err := r.Cookie(SessionCookieName)
+	if err != nil || cookie.Value == "" {
+		return nil
+	}
+
+	ctx := r.Context()
+	session, err := storage.GetSession(ctx, HashToken(cookie.Value))
+	if err != nil {
+		if !errors.Is(err, storage.ErrSessionNotFound) {
+			slog.ErrorContext(ctx, "failed to load session", "error", err)
+		}
+		return nil
+	}
+
+	user, err := storage.GetUserByID(ctx, session.UserID)
+	if err != nil {
+		slog.WarnContext(ctx, "session refers to unknown user", "userId", session.UserID, "error", err)
+		return nil
+	}
+	return user
+}
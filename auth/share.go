@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// ErrInvalidShare is returned when a sharing link is malformed, tampered with, expired or revoked
+var ErrInvalidShare = errors.New("invalid or expired sharing link")
+
+// ShareToken returns the signed token for a sharing link, "<id>.<expiry>.<signature>";
+// the signature covers the ID and expiry so neither can be altered
+func ShareToken(share *models.Share) (string, error) {
+	payload := share.ID + "." + strconv.FormatInt(share.ExpiresAt.Unix(), 10)
+	signature, err := signShare(payload)
+	if err != nil {
+		return "", err
+	}
+	return payload + "." + signature, nil
+}
+
+// VerifyShareToken checks the token's signature and expiry and returns the active share
+func VerifyShareToken(ctx context.Context, token string) (*models.Share, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidShare
+	}
+	payload := parts[0] + "." + parts[1]
+
+	expected, err := signShare(payload)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, ErrInvalidShare
+	}
+
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidShare
+	}
+	now := time.Now()
+	if !now.Before(time.Unix(expiry, 0)) {
+		return nil, ErrInvalidShare
+	}
+
+	share, err := storage.GetShare(ctx, parts[0])
+	if err != nil {
+		if errors.Is(err, storage.ErrShareNotFound) {
+			return nil, ErrInvalidShare
+		}
+		return nil, err
+	}
+	if !share.Active(now) || share.ExpiresAt.Unix() != expiry {
+		return nil, ErrInvalidShare
+	}
+	return share, nil
+}
+
+// signShare computes the URL-safe HMAC-SHA256 signature of a share payload
+func signShare(payload string) (string, error) {
+	secret, err := storage.SigningSecret()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("share:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// maxShareNameLength bounds the sharing link name
+const maxShareNameLength = 100
+
+// IssueShare creates a sharing link for the user's applications matching filter, valid for ttl
+func IssueShare(ctx context.Context, user *models.User, name string, filter models.ShareFilter, ttl time.Duration) (*models.Share, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxShareNameLength {
+		return nil, fmt.Errorf("link name must be between 1 and %d characters", maxShareNameLength)
+	}
+	if ttl <= 0 {
+		return nil, errors.New("sharing links must expire")
+	}
+	if filter.Status != "" && !models.IsValidStatus(filter.Status) {
+		return nil, fmt.Errorf("unknown status: %q", filter.Status)
+	}
+
+	// Second precision, so the expiry in the token matches the stored one exactly
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	share := models.NewShare(user.ID, name, filter, expiresAt)
+	if err := storage.CreateShare(ctx, share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
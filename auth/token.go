@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+const (
+	// tokenPrefix marks API token secrets so they are easy to recognize
+	tokenPrefix = "at_"
+	// displayPrefixLength is how much of the secret is kept to identify the token in lists
+	displayPrefixLength = 8
+	// lastUsedResolution limits how often the last-used timestamp is written
+	lastUsedResolution = time.Minute
+	// maxTokenNameLength bounds the token name
+	maxTokenNameLength = 100
+)
+
+// ErrInvalidToken is returned when a bearer token is unknown, expired or revoked
+var ErrInvalidToken = errors.New("invalid or expired API token")
+
+// IssueToken creates a new API token for the user and returns the secret, which is never stored
+func IssueToken(ctx context.Context, user *models.User, name string, scopes []string, ttl time.Duration) (string, *models.APIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxTokenNameLength {
+		return "", nil, fmt.Errorf("token name must be between 1 and %d characters", maxTokenNameLength)
+	}
+	if len(scopes) == 0 {
+		return "", nil, errors.New("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !models.IsValidScope(scope) {
+			return "", nil, fmt.Errorf("unknown scope: %q", scope)
+		}
+	}
+
+	random, err := NewToken()
+	if err != nil {
+		return "", nil, err
+	}
+	secret := tokenPrefix + random
+
+	var expiresAt *time.Time
+	if ttl > 0 {
+		t := time.Now().Add(ttl)
+		expiresAt = &t
+	}
+
+	token := models.NewAPIToken(user.ID, name, secret[:len(tokenPrefix)+displayPrefixLength], HashToken(secret), scopes, expiresAt)
+	if err := storage.CreateToken(ctx, token); err != nil {
+		return "", nil, err
+	}
+	return secret, token, nil
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", false
+	}
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// tokenUser resolves a bearer token to its token record and owner
+func tokenUser(r *http.Request, secret string) (*models.APIToken, *models.User, error) {
+	ctx := r.Context()
+	token, err := storage.GetTokenByHash(ctx, HashToken(secret))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			return nil, nil, ErrInvalidToken
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if !token.Active(now) {
+		return nil, nil, ErrInvalidToken
+	}
+
+	user, err := storage.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidToken
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := storage.TouchToken(ctx, token.UserID, token.ID, now); err != nil {
+			slog.WarnContext(ctx, "failed to record API token use", "tokenId", token.ID, "error", err)
+		}
+	}
+	return token, user, nil
+}
+
+// HasScope reports whether the request may use the given scope; browser sessions
+// have every scope, API tokens only the ones they were granted
+func HasScope(ctx context.Context, scope string) bool {
+	if token := TokenFromContext(ctx); token != nil {
+		return token.HasScope(scope)
+	}
+	return UserFromContext(ctx) != nil
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+const (
+	// WorkspaceCookieName is the cookie remembering the workspace selected in the browser
+	WorkspaceCookieName = "workspace"
+	// WorkspaceHeader selects the workspace of an API request
+	WorkspaceHeader = "X-Workspace-ID"
+)
+
+// ErrNotMember is returned when a user selects a workspace they are not a member of
+var ErrNotMember = errors.New("not a member of this workspace")
+
+// withRequestWorkspace resolves the workspace selected by the request for user. The
+// X-Workspace-ID header must name a workspace the user belongs to, otherwise the
+// request gets no workspace; a stale workspace cookie falls back to the user's own.
+func withRequestWorkspace(r *http.Request, user *models.User) context.Context {
+	ctx := r.Context()
+
+	if id := r.Header.Get(WorkspaceHeader); id != "" {
+		workspace, err := workspaceFor(ctx, user, id)
+		if err != nil {
+			if !errors.Is(err, ErrNotMember) {
+				slog.ErrorContext(ctx, "failed to load workspace membership", "error", err)
+			}
+			return ctx
+		}
+		return WithWorkspace(ctx, workspace)
+	}
+
+	if cookie, err := r.Cookie(WorkspaceCookieName); err == nil && cookie.Value != "" {
+		if workspace, err := workspaceFor(ctx, user, cookie.Value); err == nil {
+			return WithWorkspace(ctx, workspace)
+		}
+	}
+	return WithWorkspace(ctx, ownWorkspace(user))
+}
+
+// ownWorkspace returns the workspace owned by user
+func ownWorkspace(user *models.User) *models.Workspace {
+	return &models.Workspace{ID: user.ID, Name: user.Username, Role: models.RoleOwner}
+}
+
+// workspaceFor returns the workspace with the given ID as seen by user
+func workspaceFor(ctx context.Context, user *models.User, id string) (*models.Workspace, error) {
+	if id == user.ID {
+		return ownWorkspace(user), nil
+	}
+
+	membership, err := storage.GetMembership(ctx, id, user.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrMembershipNotFound) {
+			return nil, ErrNotMember
+		}
+		return nil, err
+	}
+	owner, err := storage.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, ErrNotMember
+	}
+	return &models.Workspace{ID: id, Name: owner.Username, Role: membership.Role}, nil
+}
+
+// HasRole reports whether the request's role in its workspace includes the required role
+func HasRole(ctx context.Context, role string) bool {
+	workspace := WorkspaceFromContext(ctx)
+	return workspace != nil && workspace.Can(role)
+}
+
+// ListWorkspaces returns every workspace user can select, their own first
+func ListWorkspaces(ctx context.Context, user *models.User) ([]models.Workspace, error) {
+	memberships, err := storage.ListMembershipsForUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces := []models.Workspace{*ownWorkspace(user)}
+	for _, m := range memberships {
+		owner, err := storage.GetUserByID(ctx, m.WorkspaceID)
+		if err != nil {
+			slog.WarnContext(ctx, "membership refers to unknown workspace", "workspaceId", m.WorkspaceID)
+			continue
+		}
+		workspaces = append(workspaces, models.Workspace{ID: m.WorkspaceID, Name: owner.Username, Role: m.Role})
+	}
+	return workspaces, nil
+}
+
+// SelectWorkspace remembers the workspace the browser acts on
+func SelectWorkspace(w http.ResponseWriter, r *http.Request, id string) error {
+	user := UserFromContext(r.Context())
+	if _, err := workspaceFor(r.Context(), user, id); err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     WorkspaceCookieName,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int(options.SessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   secureCookie(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// AddMember grants the user with the given username a role in owner's workspace, or
+// changes the role if they are already a member
+func AddMember(ctx context.Context, owner *models.User, username, role string) (*models.Membership, error) {
+	if !models.IsValidMemberRole(role) {
+		return nil, fmt.Errorf("unknown role: %q", role)
+	}
+	user, err := storage.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, errors.New("no user with that username")
+		}
+		return nil, err
+	}
+	if user.ID == owner.ID {
+		return nil, errors.New("you already own this workspace")
+	}
+
+	membership := models.NewMembership(owner.ID, user, role)
+	created, err := storage.SaveMembership(ctx, membership)
+	if err != nil {
+		return nil, err
+	}
+
+	action := models.AuditMemberRoleChanged
+	if created {
+		action = models.AuditMemberAdded
+	}
+	Audit(ctx, owner.ID, action, user.ID, user.Username+" as "+role)
+	return membership, nil
+}
+
+// RemoveMember removes a user from owner's workspace
+func RemoveMember(ctx context.Context, owner *models.User, userID string) error {
+	membership, err := storage.DeleteMembership(ctx, owner.ID, userID)
+	if err != nil {
+		return err
+	}
+	Audit(ctx, owner.ID, models.AuditMemberRemoved, userID, membership.Username)
+	return nil
+}
+
+// Audit records that the request's user performed action in a workspace. Failures are
+// logged rather than returned so they never undo the action itself.
+func Audit(ctx context.Context, workspaceID, action, targetID, detail string) {
+	user := UserFromContext(ctx)
+	if user == nil {
+		return
+	}
+	event := models.NewAuditEvent(workspaceID, user, action, targetID, detail)
+	if err := storage.AppendAuditEvent(ctx, event); err != nil {
+		slog.ErrorContext(ctx, "failed to record audit event", "action", action, "error", err)
+	}
+}
+
+// AuditEntry is one action recorded by AuditAll
+type AuditEntry struct {
+	Action   string
+	TargetID string
+	Detail   string
+}
+
+// AuditAll records several actions of the request's user in a workspace with a single
+// write to the audit log, such as the operations of a bulk request. Like Audit, it
+// logs failures rather than returning them.
+func AuditAll(ctx context.Context, workspaceID string, entries []AuditEntry) {
+	user := UserFromContext(ctx)
+	if user == nil || len(entries) == 0 {
+		return
+	}
+	events := make([]models.AuditEvent, 0, len(entries))
+	for _, entry := range entries {
+		events = append(events, *models.NewAuditEvent(workspaceID, user, entry.Action, entry.TargetID, entry.Detail))
+	}
+	if err := storage.AppendAuditEvents(ctx, events); err != nil {
+		slog.ErrorContext(ctx, "failed to record audit events", "count", len(events), "error", err)
+	}
+}
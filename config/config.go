@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the runtime settings for the server
+type Config struct {
+	Port      int
+	LogLevel  string
+	LogFormat string
+
+	// MetricsAddr is the address of the internal listener serving /metrics; empty
+	// disables it
+	MetricsAddr string
+
+	// MinFreeDiskMB is the free disk space below which the server reports not ready
+	MinFreeDiskMB int
+
+	// SessionTTL is how long a login session stays valid
+	SessionTTL time.Duration
+	// SessionCookieSecure is "auto", "true" or "false"
+	SessionCookieSecure string
+	// AllowSignup lets anyone register an account; the first account can always be created
+	AllowSignup bool
+
+	// CORSAllowedOrigins lists the origins allowed to call the API from a browser
+	CORSAllowedOrigins []string
+
+	// RateLimitRPS is the sustained number of API requests per second allowed per client,
+	// with bursts of up to RateLimitBurst; 0 disables rate limiting
+	RateLimitRPS   float64
+	RateLimitBurst int
+	// LoginRateLimit is the number of login and registration attempts allowed per IP
+	// address per minute; 0 disables the limit
+	LoginRateLimit int
+	// MaxRequestBytes caps the body of application create and update requests
+	MaxRequestBytes int64
+	// Limits on the size of application fields
+	MaxTags              int
+	MaxTagLength         int
+	MaxFieldLength       int
+	MaxURLLength         int
+	MaxDescriptionLength int
+
+	// IdempotencyTTL is how long responses to requests with an Idempotency-Key are kept for replay
+	IdempotencyTTL time.Duration
+
+	// ReminderInterval is how often the scheduler fires due reminders and checks for
+	// stale applications; 0 disables it
+	ReminderInterval time.Duration
+}
+
+// Load reads the configuration from environment variables, falling back to defaults
+func Load() Config {
+	return Config{
+		Port:      getEnvInt("PORT", 8080),
+		LogLevel:  getEnv("LOG_LEVEL", "info"),
+		LogFormat: getEnv("LOG_FORMAT", "text"),
+
+		MetricsAddr: getEnv("METRICS_ADDR", "127.0.0.1:9090"),
+
+		MinFreeDiskMB: getEnvInt("HEALTH_MIN_FREE_DISK_MB", 100),
+
+		SessionTTL:          getEnvDuration("SESSION_TTL", 7*24*time.Hour),
+		SessionCookieSecure: getEnv("SESSION_COOKIE_SECURE", "auto"),
+		AllowSignup:         getEnvBool("ALLOW_SIGNUP", false),
+
+		CORSAllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS"),
+
+		RateLimitRPS:         getEnvFloat("RATE_LIMIT_RPS", 10),
+		RateLimitBurst:       getEnvInt("RATE_LIMIT_BURST", 50),
+		LoginRateLimit:       getEnvInt("LOGIN_RATE_LIMIT", 10),
+		MaxRequestBytes:      int64(getEnvInt("MAX_REQUEST_BYTES", 1<<20)),
+		MaxTags:              getEnvInt("MAX_TAGS", 20),
+		MaxTagLength:         getEnvInt("MAX_TAG_LENGTH", 50),
+		MaxFieldLength:       getEnvInt("MAX_FIELD_LENGTH", 200),
+		MaxURLLength:         getEnvInt("MAX_URL_LENGTH", 2048),
+		MaxDescriptionLength: getEnvInt("MAX_DESCRIPTION_LENGTH", 20000),
+
+		IdempotencyTTL: getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+
+		ReminderInterval: getEnvDuration("REMINDER_INTERVAL", time.Minute),
+	}
+}
+
+// getEnv returns the value of an environment variable or the fallback if unset
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvList returns a comma separated environment variable as a list, skipping empty items
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvInt returns an integer environment variable or the fallback if unset or invalid
+func getEnvInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+// getEnvFloat returns a floating point environment variable or the fallback if unset or invalid
+func getEnvFloat(key string, fallback float64) float64 {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+// getEnvBool returns a boolean environment variable or the fallback if unset or invalid
+func getEnvBool(key string, fallback bool) bool {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+// getEnvDuration returns a duration environment variable (e.g. "12h") or the fallback if unset or invalid
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
@@ -1,8 +1,7 @@
 module ApplicationTracker
 
-go 1.22
+go 1.23
 
-require (
-	github.com/google/uuid v1.6.0 // indirect
-	github.com/gorilla/handlers v1.5.2 // indirect
-)
+require github.com/google/uuid v1.6.0
+
+require github.com/gorilla/handlers v1.5.2 // indirect
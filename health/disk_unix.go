@@ -0,0 +1,17 @@
+//go:build unix
+
+package health
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// freeDiskSpace returns the number of bytes available to unprivileged users on the filesystem holding dir
+func freeDiskSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, fmt.Errorf("failed to stat filesystem: %w", err)
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ApplicationTracker/storage"
+	"ApplicationTracker/ui"
+)
+
+// Status values reported by checks
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check is a named readiness check
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a single check
+type CheckResult struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the combined outcome of a set of checks
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Healthy reports whether every check passed
+func (r Report) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+// Run executes the checks in order and collects their results
+func Run(ctx context.Context, checks []Check) Report {
+	report := Report{Status: StatusOK, Checks: make([]CheckResult, 0, len(checks))}
+	for _, check := range checks {
+		start := time.Now()
+		err := check.Run(ctx)
+		result := CheckResult{
+			Name:     check.Name,
+			Status:   StatusOK,
+			Duration: time.Since(start).String(),
+		}
+		if err != nil {
+			result.Status = StatusFail
+			result.Message = err.Error()
+			report.Status = StatusFail
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+// ReadinessChecks returns the checks that must pass before the server can serve traffic
+func ReadinessChecks(minFreeDiskBytes uint64) []Check {
+	return []Check{
+		{Name: "data_dir_writable", Run: func(ctx context.Context) error {
+			return storage.CheckWritable()
+		}},
+		{Name: "applications_file", Run: func(ctx context.Context) error {
+			return storage.ValidateApplications()
+		}},
+		{Name: "templates", Run: func(ctx context.Context) error {
+			return ui.ValidateTemplates()
+		}},
+		{Name: "disk_space", Run: func(ctx context.Context) error {
+			return checkDiskSpace(storage.DataDir(), minFreeDiskBytes)
+		}},
+	}
+}
+
+// checkDiskSpace fails when the filesystem holding dir has less than minFree bytes available
+func checkDiskSpace(dir string, minFree uint64) error {
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		return err
+	}
+	if free < minFree {
+		return fmt.Errorf("only %d MB free, need at least %d MB", free>>20, minFree>>20)
+	}
+	return nil
+}
@@ -1,36 +1,82 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
 
 	"ApplicationTracker/api"
+	"ApplicationTracker/auth"
+	"ApplicationTracker/config"
+	"ApplicationTracker/logging"
+	"ApplicationTracker/metrics"
+	"ApplicationTracker/scheduler"
+	"ApplicationTracker/security"
 	"ApplicationTracker/storage"
 	"ApplicationTracker/ui"
 )
 
 func main() {
-	port := 8080
+	cfg := config.Load()
+
+	// Set up structured logging
+	if err := logging.Setup(cfg.LogLevel, cfg.LogFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to configure logging: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Initialize storage
 	if err := storage.Initialize(); err != nil {
-		log.Fatalf("Failed to initialize storage: %v", err)
+		slog.Error("failed to initialize storage", "error", err)
+		os.Exit(1)
+	}
+
+	// Configure sessions and account creation
+	auth.Configure(auth.Options{
+		SessionTTL:   cfg.SessionTTL,
+		CookieSecure: cfg.SessionCookieSecure,
+		AllowSignup:  cfg.AllowSignup,
+		MaxFormBytes: cfg.MaxRequestBytes,
+	})
+
+	// Fire due reminders in the background
+	if cfg.ReminderInterval > 0 {
+		go scheduler.Run(context.Background(), cfg.ReminderInterval)
 	}
 
 	// Create a new ServeMux
 	mux := http.NewServeMux()
 
 	// Set up API routes
-	apiRouter := api.SetupRouter()
+	apiRouter := api.SetupRouter(cfg)
 	mux.Handle("/api/", http.StripPrefix("/api", apiRouter))
 
 	// Set up UI routes
-	ui.SetupUIRouter(mux)
+	ui.SetupUIRouter(mux, cfg)
+
+	// Expose Prometheus metrics on their own listener, away from the public port
+	if cfg.MetricsAddr != "" {
+		go func() {
+			metricsMux := http.NewServeMux()
+			metricsMux.Handle("/metrics", metrics.Handler())
+			slog.Info("metrics server running", "addr", cfg.MetricsAddr)
+			if err := http.ListenAndServe(cfg.MetricsAddr, metricsMux); err != nil {
+				slog.Error("metrics server stopped", "error", err)
+			}
+		}()
+	}
 
 	// Start the server
-	fmt.Printf("Server running on port %d...\n", port)
-	fmt.Printf("UI available at http://localhost:%d\n", port)
-	fmt.Printf("API available at http://localhost:%d/api\n", port)
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", port), mux))
+	slog.Info("server running",
+		"port", cfg.Port,
+		"ui", fmt.Sprintf("http://localhost:%d", cfg.Port),
+		"api", fmt.Sprintf("http://localhost:%d/api", cfg.Port),
+	)
+	handler := logging.RequestIDMiddleware(metrics.Middleware(security.Headers(auth.Authenticate(auth.CSRF(metrics.Routes("", mux))))))
+	if err := http.ListenAndServe(fmt.Sprintf(":%d", cfg.Port), handler); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
 }
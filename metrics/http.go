@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	httpRequestsTotal = NewCounterVec("http_requests_total",
+		"Total number of HTTP requests by route, method and status code.",
+		"route", "method", "status")
+	httpRequestDuration = NewHistogramVec("http_request_duration_seconds",
+		"HTTP request latency by route, method and status code.",
+		DefaultBuckets, "route", "method", "status")
+)
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before writing it
+func (rec *statusRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// routeKey is the context key of the route a request was served by
+type routeKey struct{}
+
+// route holds the pattern of the route that served a request
+type route struct {
+	pattern string
+}
+
+// Middleware records request counts and latencies for every request, labelled with the
+// route pattern Routes recorded for it
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		served := &route{}
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), routeKey{}, served)))
+
+		label := routeLabel(served.pattern, rec.status)
+		method := methodLabel(r.Method)
+		status := strconv.Itoa(rec.status)
+		httpRequestsTotal.Inc(label, method, status)
+		httpRequestDuration.Observe(time.Since(start).Seconds(), label, method, status)
+	})
+}
+
+// Routes records the pattern of the ServeMux route that serves each request, for
+// Middleware to label it with; prefix is the path the mux is mounted under, such as
+// "/api". The innermost mux's pattern wins when muxes are nested.
+func Routes(prefix string, mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r)
+		if served, ok := r.Context().Value(routeKey{}).(*route); ok && served.pattern == "" && r.Pattern != "" {
+			served.pattern = prefix + r.Pattern
+		}
+	})
+}
+
+// routeLabel returns the route label of a request served by the route with the given
+// pattern: paths that weren't found are "unmatched" and requests answered before
+// reaching a route, such as unauthenticated ones, are "other"
+func routeLabel(pattern string, status int) string {
+	switch {
+	case status == http.StatusNotFound:
+		return "unmatched"
+	case pattern == "":
+		return "other"
+	}
+	return pattern
+}
+
+// methodLabel returns the method label of a request, collapsing nonstandard methods
+// into "other"
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return "other"
+}
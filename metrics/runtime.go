@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+)
+
+// startTime is used to report process uptime
+var startTime = time.Now()
+
+// runtimeCollector reports Go runtime statistics, reading them once per scrape
+type runtimeCollector struct{}
+
+func init() {
+	register(runtimeCollector{})
+}
+
+func (runtimeCollector) write(w io.Writer) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	gauges := []struct {
+		name, help string
+		value      float64
+	}{
+		{"go_goroutines", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine())},
+		{"go_threads", "Number of OS threads created.", float64(threadCount())},
+		{"go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", float64(mem.Alloc)},
+		{"go_memstats_sys_bytes", "Number of bytes obtained from system.", float64(mem.Sys)},
+		{"go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use.", float64(mem.HeapAlloc)},
+		{"go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", float64(mem.HeapInuse)},
+		{"go_memstats_heap_objects", "Number of allocated objects.", float64(mem.HeapObjects)},
+		{"go_memstats_last_gc_time_seconds", "Number of seconds since 1970 of last garbage collection.", float64(mem.LastGC) / 1e9},
+		{"process_uptime_seconds", "Number of seconds since the process started.", time.Since(startTime).Seconds()},
+	}
+	for _, g := range gauges {
+		writeHeader(w, g.name, g.help, "gauge")
+		fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.value))
+	}
+
+	writeHeader(w, "go_memstats_alloc_bytes_total", "Total number of bytes allocated, even if freed.", "counter")
+	fmt.Fprintf(w, "go_memstats_alloc_bytes_total %d\n", mem.TotalAlloc)
+	writeHeader(w, "go_gc_cycles_total", "Number of completed GC cycles.", "counter")
+	fmt.Fprintf(w, "go_gc_cycles_total %d\n", mem.NumGC)
+	writeHeader(w, "go_gc_pause_seconds_total", "Total time spent in GC stop-the-world pauses.", "counter")
+	fmt.Fprintf(w, "go_gc_pause_seconds_total %s\n", formatFloat(float64(mem.PauseTotalNs)/1e9))
+
+	writeHeader(w, "go_info", "Information about the Go environment.", "gauge")
+	fmt.Fprintf(w, "go_info%s 1\n", formatLabels([]string{"version"}, []string{runtime.Version()}))
+}
+
+// threadCount returns the number of OS threads created by the runtime
+func threadCount() int {
+	n, _ := runtime.ThreadCreateProfile(nil)
+	return n
+}
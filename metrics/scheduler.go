@@ -0,0 +1,29 @@
+package metrics
+
+import "time"
+
+var (
+	schedulerRunsTotal = NewCounterVec("scheduler_runs_total",
+		"Total number of scheduler runs by result.",
+		"result")
+	schedulerRunDuration = NewHistogramVec("scheduler_run_duration_seconds",
+		"Duration of scheduler runs.",
+		DefaultBuckets)
+	remindersFiredTotal = NewCounterVec("reminders_fired_total",
+		"Total number of reminders fired into notifications.")
+	applicationsGhostedTotal = NewCounterVec("applications_ghosted_total",
+		"Total number of stale applications moved to ghosted by the scheduler.")
+)
+
+// ObserveSchedulerRun records a scheduler run started at start that fired fired
+// reminders and ghosted ghosted applications, or failed with err
+func ObserveSchedulerRun(start time.Time, fired, ghosted int, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	schedulerRunsTotal.Inc(result)
+	schedulerRunDuration.Observe(time.Since(start).Seconds())
+	remindersFiredTotal.Add(float64(fired))
+	applicationsGhostedTotal.Add(float64(ghosted))
+}
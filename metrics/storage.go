@@ -0,0 +1,40 @@
+package metrics
+
+import "time"
+
+var (
+	storageOperationDuration = NewHistogramVec("storage_operation_duration_seconds",
+		"Duration of storage file reads and writes.",
+		DefaultBuckets, "operation")
+	storageFileSize = NewGaugeVec("storage_file_size_bytes",
+		"Size of the storage data files in bytes.",
+		"file")
+	applicationsByStatus = NewGaugeVec("applications",
+		"Number of stored applications by status.",
+		"status")
+	staleApplications = NewGaugeVec("applications_stale",
+		"Number of stored applications flagged stale.")
+)
+
+// ObserveStorage records the duration of a storage operation ("read" or "write") started at start
+func ObserveStorage(operation string, start time.Time) {
+	storageOperationDuration.Observe(time.Since(start).Seconds(), operation)
+}
+
+// SetStorageFileSize records the current size of a data file
+func SetStorageFileSize(file string, bytes int) {
+	storageFileSize.Set(float64(bytes), file)
+}
+
+// SetApplicationCounts replaces the per-status application counts
+func SetApplicationCounts(counts map[string]int) {
+	applicationsByStatus.Reset()
+	for status, count := range counts {
+		applicationsByStatus.Set(float64(count), status)
+	}
+}
+
+// SetStaleApplications records the number of applications flagged stale
+func SetStaleApplications(count int) {
+	staleApplications.Set(float64(count))
+}
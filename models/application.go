@@ -6,52 +6,113 @@ import (
 
 // Application represents a job application
 type Application struct {
-	ID          string    `json:"id"`
-	Company     string    `json:"company"`
-	Position    string    `json:"position"`
-	Description string    `json:"description"`
-	URL         string    `json:"url"`
-	Status      string    `json:"status"`
-	Tags        []string  `json:"tags"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          string `json:"id"`
+	OwnerID     string `json:"ownerId"`
+	Company     string `json:"company"`
+	CompanyID   string `json:"companyId,omitempty"`
+	Position    string `json:"position"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Status      string `json:"status"`
+	// StatusChangedAt is when the status was last set to a different value; it is zero
+	// for applications whose status changed before it was recorded
+	StatusChangedAt time.Time `json:"statusChangedAt"`
+	// StaleAt is when the scheduler flagged the application as stale; the flag lapses
+	// once the application is updated again
+	StaleAt *time.Time `json:"staleAt,omitempty"`
+	// Compensation is the pay in the job posting and Offer the details of an offer
+	Compensation *Compensation `json:"compensation,omitempty"`
+	Offer        *Compensation `json:"offer,omitempty"`
+	// Location is where the job is based, WorkMode whether it is remote, hybrid or
+	// onsite, and TimeZone the UTC offsets it expects people to work from
+	Location *Location            `json:"location,omitempty"`
+	WorkMode string               `json:"workMode,omitempty"`
+	TimeZone *TimeZoneRequirement `json:"timeZone,omitempty"`
+	// AppliedAt is when the application was sent, which may be before it was logged;
+	// PostedAt and ClosingAt are when the job was posted and when applications close
+	AppliedAt time.Time  `json:"appliedAt"`
+	PostedAt  *time.Time `json:"postedAt,omitempty"`
+	ClosingAt *time.Time `json:"closingAt,omitempty"`
+	// LastActivityAt is when someone last worked on the application: when it was sent,
+	// edited or had its status changed. Automatic changes such as auto-tags don't count.
+	LastActivityAt time.Time `json:"lastActivityAt"`
+	// Fields holds the values of the workspace's custom fields, by field key
+	Fields    map[string]string `json:"fields,omitempty"`
+	Tags      []string          `json:"tags"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
 }
 
 // ApplicationStatus defines the possible statuses for a job application
 var ApplicationStatus = struct {
-	Applied     string
-	InProgress  string
-	Rejected    string
-	Accepted    string
+	Applied    string
+	InProgress string
+	Rejected   string
+	Accepted   string
+	Ghosted    string
 }{
-	Applied:     "applied",
-	InProgress:  "in_progress",
-	Rejected:    "rejected",
-	Accepted:    "accepted",
+	Applied:    "applied",
+	InProgress: "in_progress",
+	Rejected:   "rejected",
+	Accepted:   "accepted",
+	Ghosted:    "ghosted",
+}
+
+// IsValidStatus reports whether status is one of the known application statuses
+func IsValidStatus(status string) bool {
+	switch status {
+	case ApplicationStatus.Applied, ApplicationStatus.InProgress, ApplicationStatus.Rejected, ApplicationStatus.Accepted, ApplicationStatus.Ghosted:
+		return true
+	}
+	return false
+}
+
+// StatusName returns the display name of an application status
+func StatusName(status string) string {
+	switch status {
+	case ApplicationStatus.Applied:
+		return "Applied"
+	case ApplicationStatus.InProgress:
+		return "In Progress"
+	case ApplicationStatus.Rejected:
+		return "Rejected"
+	case ApplicationStatus.Accepted:
+		return "Accepted"
+	case ApplicationStatus.Ghosted:
+		return "Ghosted"
+	}
+	return status
 }
 
 // NewApplication creates a new application with default values
 func NewApplication(company, position, description, url string, tags []string) *Application {
 	now := time.Now()
 	return &Application{
-		ID:          generateID(),
-		Company:     company,
-		Position:    position,
-		Description: description,
-		URL:         url,
-		Status:      ApplicationStatus.Applied,
-		Tags:        tags,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:              generateID(),
+		Company:         company,
+		Position:        position,
+		Description:     description,
+		URL:             url,
+		Status:          ApplicationStatus.Applied,
+		Tags:            NormalizeTags(tags),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		StatusChangedAt: now,
+		AppliedAt:       now,
+		LastActivityAt:  now,
 	}
 }
 
-// AddTag adds a tag to the application if it doesn't already exist
+// RecordActivity records that someone worked on the application just now
+func (a *Application) RecordActivity() {
+	a.LastActivityAt = time.Now()
+}
+
+// AddTag adds a tag to the application if it doesn't already exist; tags are normalized
 func (a *Application) AddTag(tag string) {
-	for _, t := range a.Tags {
-		if t == tag {
-			return
-		}
+	tag = NormalizeTag(tag)
+	if tag == "" || containsTag(a.Tags, tag) {
+		return
 	}
 	a.Tags = append(a.Tags, tag)
 	a.UpdatedAt = time.Now()
@@ -59,6 +120,7 @@ func (a *Application) AddTag(tag string) {
 
 // RemoveTag removes a tag from the application
 func (a *Application) RemoveTag(tag string) {
+	tag = NormalizeTag(tag)
 	for i, t := range a.Tags {
 		if t == tag {
 			a.Tags = append(a.Tags[:i], a.Tags[i+1:]...)
@@ -68,8 +130,32 @@ func (a *Application) RemoveTag(tag string) {
 	}
 }
 
-// UpdateStatus updates the application status
+// UpdateStatus updates the application status, recording when it changed
 func (a *Application) UpdateStatus(status string) {
+	now := time.Now()
+	if status != a.Status {
+		a.StatusChangedAt = now
+	}
 	a.Status = status
-	a.UpdatedAt = time.Now()
-}
\ No newline at end of file
+	a.UpdatedAt = now
+}
+
+// StatusSince returns when the application entered its current status; for statuses
+// set before changes were recorded it falls back to the last update, which is no
+// earlier than the change
+func (a Application) StatusSince() time.Time {
+	if a.StatusChangedAt.IsZero() {
+		return a.UpdatedAt
+	}
+	return a.StatusChangedAt
+}
+
+// IsStale reports whether the application is flagged stale and hasn't seen activity since
+func (a Application) IsStale() bool {
+	return a.StaleAt != nil && !a.ActivityAt().After(*a.StaleAt)
+}
+
+// DaysSinceUpdate returns the number of whole days since the application last saw activity
+func (a Application) DaysSinceUpdate() int {
+	return int(time.Since(a.ActivityAt()).Hours() / 24)
+}
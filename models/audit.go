@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// Audit event actions
+const (
+	AuditApplicationCreated       = "application.created"
+	AuditApplicationUpdated       = "application.updated"
+	AuditApplicationStatusChanged = "application.status_changed"
+	AuditApplicationDeleted       = "application.deleted"
+	AuditApplicationMerged        = "application.merged"
+	AuditCommentCreated           = "comment.created"
+	AuditNoteCreated              = "note.created"
+	AuditNoteUpdated              = "note.updated"
+	AuditNoteDeleted              = "note.deleted"
+	AuditInterviewScheduled       = "interview.scheduled"
+	AuditInterviewUpdated         = "interview.updated"
+	AuditInterviewDeleted         = "interview.deleted"
+	AuditContactCreated           = "contact.created"
+	AuditContactUpdated           = "contact.updated"
+	AuditContactDeleted           = "contact.deleted"
+	AuditContactLinked            = "contact.linked"
+	AuditContactUnlinked          = "contact.unlinked"
+	AuditReminderCreated          = "reminder.created"
+	AuditReminderUpdated          = "reminder.updated"
+	AuditReminderDeleted          = "reminder.deleted"
+	AuditCompanyCreated           = "company.created"
+	AuditCompanyUpdated           = "company.updated"
+	AuditCompanyDeleted           = "company.deleted"
+	AuditCompaniesMerged          = "company.merged"
+	AuditTagRenamed               = "tag.renamed"
+	AuditTagsMerged               = "tag.merged"
+	AuditMemberAdded              = "member.added"
+	AuditMemberRoleChanged        = "member.role_changed"
+	AuditMemberRemoved            = "member.removed"
+)
+
+// AuditEvent records who did what in a workspace
+type AuditEvent struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspaceId"`
+	ActorID     string    `json:"actorId"`
+	ActorName   string    `json:"actorName"`
+	Action      string    `json:"action"`
+	TargetID    string    `json:"targetId,omitempty"`
+	Detail      string    `json:"detail,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// NewAuditEvent creates an audit event for an action by actor in a workspace
+func NewAuditEvent(workspaceID string, actor *User, action, targetID, detail string) *AuditEvent {
+	return &AuditEvent{
+		ID:          generateID(),
+		WorkspaceID: workspaceID,
+		ActorID:     actor.ID,
+		ActorName:   actor.Username,
+		Action:      action,
+		TargetID:    targetID,
+		Detail:      detail,
+		CreatedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Auto-tag rule types
+const (
+	AutoTagKeyword = "keyword"
+	AutoTagRegex   = "regex"
+	AutoTagHost    = "host"
+)
+
+// AutoTagTypes lists the kinds of auto-tag rules
+var AutoTagTypes = []string{AutoTagKeyword, AutoTagRegex, AutoTagHost}
+
+// MaxAutoTagPatternLength bounds the length of an auto-tag rule pattern
+const MaxAutoTagPatternLength = 200
+
+// AutoTagRule adds a tag to applications that match a pattern: a keyword found as a
+// whole word in the company, position or description, a regular expression matched
+// against the same text, or the host of the posting URL
+type AutoTagRule struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspaceId"`
+	Tag         string    `json:"tag"`
+	Type        string    `json:"type"`
+	Pattern     string    `json:"pattern"`
+	CreatedAt   time.Time `json:"createdAt"`
+
+	re *regexp.Regexp
+}
+
+// AutoTagChange lists the tags auto-tag rules add to an application
+type AutoTagChange struct {
+	ApplicationID string   `json:"applicationId"`
+	Company       string   `json:"company"`
+	Position      string   `json:"position"`
+	Tags          []string `json:"tags"`
+}
+
+// NewAutoTagRule creates an auto-tag rule, normalizing the tag and the pattern of host rules
+func NewAutoTagRule(workspaceID, tag, ruleType, pattern string) (*AutoTagRule, error) {
+	rule := &AutoTagRule{
+		ID:          generateID(),
+		WorkspaceID: workspaceID,
+		Tag:         NormalizeTag(tag),
+		Type:        ruleType,
+		Pattern:     strings.TrimSpace(pattern),
+		CreatedAt:   time.Now(),
+	}
+	if ruleType == AutoTagHost {
+		rule.Pattern = strings.TrimPrefix(strings.ToLower(rule.Pattern), "www.")
+	}
+
+	if rule.Tag == "" {
+		return nil, errors.New("tag is required")
+	}
+	if rule.Pattern == "" {
+		return nil, errors.New("pattern is required")
+	}
+	if len(rule.Pattern) > MaxAutoTagPatternLength {
+		return nil, fmt.Errorf("pattern must not exceed %d characters", MaxAutoTagPatternLength)
+	}
+	switch ruleType {
+	case AutoTagKeyword:
+	case AutoTagRegex:
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+	case AutoTagHost:
+		if strings.ContainsAny(rule.Pattern, "/:@ ") {
+			return nil, errors.New("host must be a domain name such as boards.greenhouse.io")
+		}
+	default:
+		return nil, fmt.Errorf("rule type must be one of: %s", strings.Join(AutoTagTypes, ", "))
+	}
+	return rule, nil
+}
+
+// compile prepares the regular expression of keyword and regex rules
+func (r *AutoTagRule) compile() *regexp.Regexp {
+	if r.re != nil {
+		return r.re
+	}
+	var err error
+	switch r.Type {
+	case AutoTagKeyword:
+		// Letters and digits must not touch the keyword, so "go" doesn't match "google"
+		r.re, err = regexp.Compile(`(?i)(^|[^\pL\pN])` + regexp.QuoteMeta(r.Pattern) + `($|[^\pL\pN])`)
+	case AutoTagRegex:
+		r.re, err = regexp.Compile(r.Pattern)
+	}
+	if err != nil {
+		return nil
+	}
+	return r.re
+}
+
+// Matches reports whether the rule applies to an application
+func (r *AutoTagRule) Matches(app Application) bool {
+	if r.Type == AutoTagHost {
+		u, err := url.Parse(strings.TrimSpace(app.URL))
+		if err != nil {
+			return false
+		}
+		host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		return host != "" && (host == r.Pattern || strings.HasSuffix(host, "."+r.Pattern))
+	}
+
+	re := r.compile()
+	if re == nil {
+		return false
+	}
+	return re.MatchString(app.Company + "\n" + app.Position + "\n" + app.Description)
+}
+
+// AutoTags returns the tags the rules would add to an application, in rule order,
+// leaving out tags it already has
+func AutoTags(app Application, rules []AutoTagRule) []string {
+	var tags []string
+	for i := range rules {
+		rule := &rules[i]
+		if !app.HasTag(rule.Tag) && !containsTag(tags, rule.Tag) && rule.Matches(app) {
+			tags = append(tags, rule.Tag)
+		}
+	}
+	return tags
+}
+
+// ApplyAutoTags adds the tags the rules match to the application, stopping once it has
+// maxTags tags, and returns the tags added
+func (a *Application) ApplyAutoTags(rules []AutoTagRule, maxTags int) []string {
+	var added []string
+	for _, tag := range AutoTags(*a, rules) {
+		if len(a.Tags) >= maxTags {
+			break
+		}
+		a.AddTag(tag)
+		added = append(added, tag)
+	}
+	return added
+}
@@ -0,0 +1,37 @@
+package models
+
+// Bulk operation actions
+const (
+	BulkSetStatus = "set_status"
+	BulkAddTag    = "add_tag"
+	BulkRemoveTag = "remove_tag"
+	BulkDelete    = "delete"
+)
+
+// BulkOperation is one change to one application in a bulk request
+type BulkOperation struct {
+	ID     string `json:"id"`
+	Action string `json:"action"`
+	Status string `json:"status,omitempty"`
+	Tag    string `json:"tag,omitempty"`
+}
+
+// BulkResult reports the outcome of one bulk operation
+type BulkResult struct {
+	Index          int          `json:"index"`
+	ID             string       `json:"id"`
+	Action         string       `json:"action"`
+	Success        bool         `json:"success"`
+	Error          string       `json:"error,omitempty"`
+	PreviousStatus string       `json:"previousStatus,omitempty"`
+	Application    *Application `json:"application,omitempty"`
+}
+
+// IsValidBulkAction reports whether action is one of the known bulk operation actions
+func IsValidBulkAction(action string) bool {
+	switch action {
+	case BulkSetStatus, BulkAddTag, BulkRemoveTag, BulkDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// MaxCommentLength bounds the length of a comment body
+const MaxCommentLength = 2000
+
+// Comment is a remark left on an application by a workspace member
+type Comment struct {
+	ID            string    `json:"id"`
+	WorkspaceID   string    `json:"workspaceId"`
+	ApplicationID string    `json:"applicationId"`
+	AuthorID      string    `json:"authorId"`
+	AuthorName    string    `json:"authorName"`
+	Body          string    `json:"body"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// NewComment creates a comment by author on an application
+func NewComment(workspaceID, applicationID string, author *User, body string) *Comment {
+	return &Comment{
+		ID:            generateID(),
+		WorkspaceID:   workspaceID,
+		ApplicationID: applicationID,
+		AuthorID:      author.ID,
+		AuthorName:    author.Username,
+		Body:          body,
+		CreatedAt:     time.Now(),
+	}
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CompanySizes lists the headcount ranges a company can be given
+var CompanySizes = []string{"1-10", "11-50", "51-200", "201-1000", "1001-5000", "5000+"}
+
+// MaxCompanyAliases bounds the number of aliases of a company
+const MaxCompanyAliases = 20
+
+// CompanyDetails are the fields of a company set by the user
+type CompanyDetails struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+	Website string   `json:"website,omitempty"`
+	Size    string   `json:"size,omitempty"`
+	Notes   string   `json:"notes,omitempty"`
+}
+
+// Company is an employer that applications refer to. Applications name companies in
+// free text; names and aliases that normalize alike, such as "Google LLC" and "google",
+// resolve to the same company.
+type Company struct {
+	ID          string `json:"id"`
+	WorkspaceID string `json:"workspaceId"`
+	CompanyDetails
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CompanyHistory summarizes the applications to a company
+type CompanyHistory struct {
+	Applications  int            `json:"applications"`
+	ByStatus      map[string]int `json:"byStatus"`
+	FirstApplied  *time.Time     `json:"firstApplied,omitempty"`
+	LatestApplied *time.Time     `json:"latestApplied,omitempty"`
+}
+
+// CompanySummary is a company with the history of applications to it
+type CompanySummary struct {
+	Company
+	History CompanyHistory `json:"history"`
+}
+
+// NewCompany creates a company, validating its details
+func NewCompany(workspaceID string, details CompanyDetails) (*Company, error) {
+	if err := details.normalize(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Company{
+		ID:             generateID(),
+		WorkspaceID:    workspaceID,
+		CompanyDetails: details,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// Update replaces the details of the company, validating them
+func (c *Company) Update(details CompanyDetails) error {
+	if err := details.normalize(); err != nil {
+		return err
+	}
+	c.CompanyDetails = details
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// normalize trims and validates the details, dropping aliases that duplicate the name
+// or each other
+func (d *CompanyDetails) normalize() error {
+	d.Name = strings.TrimSpace(d.Name)
+	d.Website = strings.TrimSpace(d.Website)
+	d.Size = strings.TrimSpace(d.Size)
+	d.Notes = strings.TrimSpace(d.Notes)
+
+	if NormalizeCompany(d.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(d.Name) > 200 {
+		return errors.New("name must not exceed 200 characters")
+	}
+
+	seen := map[string]bool{NormalizeCompany(d.Name): true}
+	aliases := []string{}
+	for _, alias := range d.Aliases {
+		alias = strings.TrimSpace(alias)
+		key := NormalizeCompany(alias)
+		if key == "" || seen[key] {
+			continue
+		}
+		if len(alias) > 200 {
+			return errors.New("aliases must not exceed 200 characters")
+		}
+		seen[key] = true
+		aliases = append(aliases, alias)
+	}
+	if len(aliases) > MaxCompanyAliases {
+		return fmt.Errorf("a company can have at most %d aliases", MaxCompanyAliases)
+	}
+	d.Aliases = aliases
+
+	if d.Website != "" {
+		u, err := url.Parse(d.Website)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("website must be an http or https URL")
+		}
+	}
+	if d.Size != "" && !slices.Contains(CompanySizes, d.Size) {
+		return fmt.Errorf("size must be one of: %s", strings.Join(CompanySizes, ", "))
+	}
+	if len(d.Notes) > MaxNoteLength {
+		return fmt.Errorf("notes must not exceed %d characters", MaxNoteLength)
+	}
+	return nil
+}
+
+// Keys returns the normalized name and aliases of the company
+func (c Company) Keys() []string {
+	keys := []string{NormalizeCompany(c.Name)}
+	for _, alias := range c.Aliases {
+		keys = append(keys, NormalizeCompany(alias))
+	}
+	return keys
+}
+
+// HasName reports whether name normalizes like the company's name or one of its aliases
+func (c Company) HasName(name string) bool {
+	key := NormalizeCompany(name)
+	return key != "" && slices.Contains(c.Keys(), key)
+}
+
+// Absorb adds the name and aliases of another company as aliases, for merged companies
+func (c *Company) Absorb(other Company) {
+	for _, name := range append([]string{other.Name}, other.Aliases...) {
+		if !c.HasName(name) && len(c.Aliases) < MaxCompanyAliases {
+			c.Aliases = append(c.Aliases, name)
+		}
+	}
+	c.UpdatedAt = time.Now()
+}
+
+// NewCompanyHistory summarizes applications to a company
+func NewCompanyHistory(applications []Application) CompanyHistory {
+	history := CompanyHistory{Applications: len(applications), ByStatus: map[string]int{}}
+	for _, app := range applications {
+		history.ByStatus[app.Status]++
+		applied := app.AppliedAt
+		if history.FirstApplied == nil || applied.Before(*history.FirstApplied) {
+			history.FirstApplied = &applied
+		}
+		if history.LatestApplied == nil || applied.After(*history.LatestApplied) {
+			history.LatestApplied = &applied
+		}
+	}
+	return history
+}
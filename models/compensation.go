@@ -0,0 +1,273 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxBenefitsLength bounds the length of the benefits notes of a compensation
+const MaxBenefitsLength = 2000
+
+// MaxCompensationAmount bounds the yearly value of each amount of a compensation, which
+// keeps annualized amounts and totals far from overflowing
+const MaxCompensationAmount = 1_000_000_000
+
+// HoursPerYear converts hourly pay to a yearly amount, at 40 hours for 52 weeks
+const HoursPerYear = 2080
+
+// Pay periods of the base amounts of a compensation
+const (
+	PayPerYear  = "year"
+	PayPerMonth = "month"
+	PayPerHour  = "hour"
+)
+
+// Compensation is the pay of a job, either the range in a posting or the details of
+// an offer. Amounts are whole units of Currency; the base is paid per Period while the
+// bonus and equity are yearly values.
+type Compensation struct {
+	BaseMin  int64  `json:"baseMin,omitempty"`
+	BaseMax  int64  `json:"baseMax,omitempty"`
+	Currency string `json:"currency,omitempty"`
+	Period   string `json:"period,omitempty"`
+	Bonus    int64  `json:"bonus,omitempty"`
+	Equity   int64  `json:"equity,omitempty"`
+	Benefits string `json:"benefits,omitempty"`
+}
+
+// IsZero reports whether nothing about the compensation is known
+func (c Compensation) IsZero() bool {
+	return c == Compensation{}
+}
+
+// Normalize trims and upper-cases the currency, defaults the period to yearly, and
+// checks the amounts make sense
+func (c *Compensation) Normalize() error {
+	c.Currency = strings.ToUpper(strings.TrimSpace(c.Currency))
+	c.Period = strings.ToLower(strings.TrimSpace(c.Period))
+	c.Benefits = strings.TrimSpace(c.Benefits)
+	if *c == (Compensation{Period: c.Period}) {
+		// A period alone, as an empty form sends, says nothing
+		*c = Compensation{}
+		return nil
+	}
+	if c.Period == "" {
+		c.Period = PayPerYear
+	}
+
+	if c.BaseMin < 0 || c.BaseMax < 0 || c.Bonus < 0 || c.Equity < 0 {
+		return errors.New("compensation amounts must not be negative")
+	}
+	if c.BaseMax > 0 && c.BaseMin > c.BaseMax {
+		return errors.New("baseMin must not exceed baseMax")
+	}
+	if c.Period != PayPerYear && c.Period != PayPerMonth && c.Period != PayPerHour {
+		return fmt.Errorf("period must be %q, %q or %q", PayPerYear, PayPerMonth, PayPerHour)
+	}
+	tooLarge := c.Bonus > MaxCompensationAmount || c.Equity > MaxCompensationAmount
+	for _, amount := range []int64{c.BaseMin, c.BaseMax} {
+		if amount > MaxCompensationAmount || c.annualize(amount) > MaxCompensationAmount {
+			tooLarge = true
+		}
+	}
+	if tooLarge {
+		return fmt.Errorf("compensation amounts must not exceed %s a year", FormatMoney(MaxCompensationAmount, ""))
+	}
+	hasAmount := c.BaseMin > 0 || c.BaseMax > 0 || c.Bonus > 0 || c.Equity > 0
+	if hasAmount && len(c.Currency) != 3 {
+		return errors.New("currency must be a three-letter code such as USD or EUR")
+	}
+	for _, r := range c.Currency {
+		if r < 'A' || r > 'Z' {
+			return errors.New("currency must be a three-letter code such as USD or EUR")
+		}
+	}
+	if len(c.Benefits) > MaxBenefitsLength {
+		return fmt.Errorf("benefits must not exceed %d characters", MaxBenefitsLength)
+	}
+	return nil
+}
+
+// annualize converts an amount of base pay to a yearly amount
+func (c Compensation) annualize(amount int64) int64 {
+	switch c.Period {
+	case PayPerMonth:
+		return amount * 12
+	case PayPerHour:
+		return amount * HoursPerYear
+	}
+	return amount
+}
+
+// AnnualBaseRange returns the yearly base pay range; a range with only one end is a
+// single amount
+func (c Compensation) AnnualBaseRange() (int64, int64) {
+	low, high := c.BaseMin, c.BaseMax
+	if low == 0 {
+		low = high
+	}
+	if high == 0 {
+		high = low
+	}
+	return c.annualize(low), c.annualize(high)
+}
+
+// AnnualTotal returns the yearly value of the compensation: the middle of the base
+// range plus the bonus and equity
+func (c Compensation) AnnualTotal() int64 {
+	low, high := c.AnnualBaseRange()
+	return (low+high)/2 + c.Bonus + c.Equity
+}
+
+// HasBase reports whether the compensation includes a base amount
+func (c Compensation) HasBase() bool {
+	return c.BaseMin > 0 || c.BaseMax > 0
+}
+
+// FormatMoney formats an amount with thousands separators and its currency, such as
+// "120,000 USD"
+func FormatMoney(amount int64, currency string) string {
+	digits := fmt.Sprint(amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return strings.TrimSpace(b.String() + " " + currency)
+}
+
+// Money formats an amount in the compensation's currency
+func (c Compensation) Money(amount int64) string {
+	return FormatMoney(amount, c.Currency)
+}
+
+// BaseText describes the base pay, such as "120,000 - 140,000 USD per year"
+func (c Compensation) BaseText() string {
+	switch {
+	case !c.HasBase():
+		return ""
+	case c.BaseMin > 0 && c.BaseMax > 0 && c.BaseMin != c.BaseMax:
+		return fmt.Sprintf("%s - %s per %s", FormatMoney(c.BaseMin, ""), c.Money(c.BaseMax), c.Period)
+	case c.BaseMin > 0:
+		return fmt.Sprintf("%s per %s", c.Money(c.BaseMin), c.Period)
+	default:
+		return fmt.Sprintf("%s per %s", c.Money(c.BaseMax), c.Period)
+	}
+}
+
+// Pay returns the compensation that best describes what an application pays: the
+// offer once there is one, otherwise the posted range, or nil if neither is known
+func (a Application) Pay() *Compensation {
+	if a.Offer != nil && !a.Offer.IsZero() {
+		return a.Offer
+	}
+	if a.Compensation != nil && !a.Compensation.IsZero() {
+		return a.Compensation
+	}
+	return nil
+}
+
+// SalaryFilter selects applications by their yearly base pay; zero bounds are open
+// and an empty currency matches any
+type SalaryFilter struct {
+	Min      int64
+	Max      int64
+	Currency string
+}
+
+// IsZero reports whether the filter selects every application
+func (f SalaryFilter) IsZero() bool {
+	return f == SalaryFilter{}
+}
+
+// Matches reports whether the yearly base range of the application's pay overlaps the
+// filter's range in its currency; applications without a known base never match a
+// filter with a range
+func (f SalaryFilter) Matches(app Application) bool {
+	if f.IsZero() {
+		return true
+	}
+	pay := app.Pay()
+	if pay == nil {
+		return false
+	}
+	if f.Currency != "" && !strings.EqualFold(f.Currency, pay.Currency) {
+		return false
+	}
+	if f.Min == 0 && f.Max == 0 {
+		return true
+	}
+	if !pay.HasBase() {
+		return false
+	}
+	low, high := pay.AnnualBaseRange()
+	return (f.Min == 0 || high >= f.Min) && (f.Max == 0 || low <= f.Max)
+}
+
+// FilterBySalary returns the applications the filter matches
+func FilterBySalary(applications []Application, filter SalaryFilter) []Application {
+	if filter.IsZero() {
+		return applications
+	}
+	result := []Application{}
+	for _, app := range applications {
+		if filter.Matches(app) {
+			result = append(result, app)
+		}
+	}
+	return result
+}
+
+// OfferSummary is an offer on an application with its yearly value, for comparing offers
+type OfferSummary struct {
+	ApplicationID string       `json:"applicationId"`
+	Company       string       `json:"company"`
+	Position      string       `json:"position"`
+	Status        string       `json:"status"`
+	Offer         Compensation `json:"offer"`
+	AnnualBaseMin int64        `json:"annualBaseMin"`
+	AnnualBaseMax int64        `json:"annualBaseMax"`
+	AnnualTotal   int64        `json:"annualTotal"`
+	// Best marks the offer with the highest yearly total in its currency
+	Best bool `json:"best"`
+}
+
+// CompareOffers returns the offers on accepted and in-progress applications, highest
+// yearly total first within each currency
+func CompareOffers(applications []Application) []OfferSummary {
+	offers := []OfferSummary{}
+	for _, app := range applications {
+		if app.Status != ApplicationStatus.Accepted && app.Status != ApplicationStatus.InProgress {
+			continue
+		}
+		if app.Offer == nil || app.Offer.IsZero() {
+			continue
+		}
+		low, high := app.Offer.AnnualBaseRange()
+		offers = append(offers, OfferSummary{
+			ApplicationID: app.ID,
+			Company:       app.Company,
+			Position:      app.Position,
+			Status:        app.Status,
+			Offer:         *app.Offer,
+			AnnualBaseMin: low,
+			AnnualBaseMax: high,
+			AnnualTotal:   app.Offer.AnnualTotal(),
+		})
+	}
+	sort.SliceStable(offers, func(i, j int) bool {
+		if offers[i].Offer.Currency != offers[j].Offer.Currency {
+			return offers[i].Offer.Currency < offers[j].Offer.Currency
+		}
+		return offers[i].AnnualTotal > offers[j].AnnualTotal
+	})
+	for i := range offers {
+		offers[i].Best = offers[i].AnnualTotal > 0 &&
+			(i == 0 || offers[i-1].Offer.Currency != offers[i].Offer.Currency)
+	}
+	return offers
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Contact roles
+const (
+	ContactRecruiter     = "recruiter"
+	ContactReferrer      = "referrer"
+	ContactHiringManager = "hiring_manager"
+	ContactInterviewer   = "interviewer"
+	ContactOther         = "other"
+)
+
+// ContactRoles lists the roles a contact can have in the hiring process
+var ContactRoles = []string{ContactRecruiter, ContactReferrer, ContactHiringManager, ContactInterviewer, ContactOther}
+
+// contactRoleNames describes contact roles for display
+var contactRoleNames = map[string]string{
+	ContactRecruiter:     "Recruiter",
+	ContactReferrer:      "Referrer",
+	ContactHiringManager: "Hiring Manager",
+	ContactInterviewer:   "Interviewer",
+	ContactOther:         "Other",
+}
+
+// maxContactFieldLength bounds the length of a contact's name, company and email
+const maxContactFieldLength = 200
+
+// ContactDetails are the fields of a contact set by the user
+type ContactDetails struct {
+	Name        string `json:"name"`
+	Role        string `json:"role"`
+	Company     string `json:"company,omitempty"`
+	Email       string `json:"email,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+	LinkedInURL string `json:"linkedinUrl,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+}
+
+// Contact is a person involved in job applications, such as a recruiter or referrer,
+// linked to any number of applications
+type Contact struct {
+	ID          string `json:"id"`
+	WorkspaceID string `json:"workspaceId"`
+	ContactDetails
+	ApplicationIDs []string  `json:"applicationIds"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// NewContact creates a contact, validating its details
+func NewContact(workspaceID string, details ContactDetails) (*Contact, error) {
+	if err := details.normalize(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Contact{
+		ID:             generateID(),
+		WorkspaceID:    workspaceID,
+		ContactDetails: details,
+		ApplicationIDs: []string{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// Update replaces the details of the contact, validating them
+func (c *Contact) Update(details ContactDetails) error {
+	if err := details.normalize(); err != nil {
+		return err
+	}
+	c.ContactDetails = details
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// normalize trims and validates the details, defaulting the role to "other"
+func (d *ContactDetails) normalize() error {
+	d.Name = strings.TrimSpace(d.Name)
+	d.Role = strings.TrimSpace(d.Role)
+	d.Company = strings.TrimSpace(d.Company)
+	d.Email = strings.TrimSpace(d.Email)
+	d.Phone = strings.TrimSpace(d.Phone)
+	d.LinkedInURL = strings.TrimSpace(d.LinkedInURL)
+	d.Notes = strings.TrimSpace(d.Notes)
+
+	if d.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(d.Name) > maxContactFieldLength || len(d.Company) > maxContactFieldLength || len(d.Email) > maxContactFieldLength {
+		return fmt.Errorf("name, company and email must not exceed %d characters", maxContactFieldLength)
+	}
+	if d.Role == "" {
+		d.Role = ContactOther
+	}
+	if !slices.Contains(ContactRoles, d.Role) {
+		return fmt.Errorf("role must be one of: %s", strings.Join(ContactRoles, ", "))
+	}
+	if d.Email != "" {
+		if addr, err := mail.ParseAddress(d.Email); err != nil || addr.Address != d.Email {
+			return errors.New("email must be a valid email address")
+		}
+	}
+	if d.Phone != "" && (len(d.Phone) > 40 || strings.Trim(d.Phone, "0123456789+-(). ") != "") {
+		return errors.New("phone may only contain digits, spaces and + - ( ) .")
+	}
+	if d.LinkedInURL != "" {
+		u, err := url.Parse(d.LinkedInURL)
+		host := ""
+		if err == nil {
+			host = strings.ToLower(u.Hostname())
+		}
+		if err != nil || u.Scheme != "https" || (host != "linkedin.com" && !strings.HasSuffix(host, ".linkedin.com")) {
+			return errors.New("linkedinUrl must be an https://linkedin.com URL")
+		}
+	}
+	if len(d.Notes) > MaxNoteLength {
+		return fmt.Errorf("notes must not exceed %d characters", MaxNoteLength)
+	}
+	return nil
+}
+
+// RoleName describes the role of the contact for display
+func (d ContactDetails) RoleName() string {
+	if name, ok := contactRoleNames[d.Role]; ok {
+		return name
+	}
+	return d.Role
+}
+
+// Matches reports whether the contact's name, company, email, role or notes contain
+// query, ignoring case
+func (c Contact) Matches(query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	for _, field := range []string{c.Name, c.Company, c.Email, c.RoleName(), c.Notes} {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLinked reports whether the contact is linked to an application
+func (c *Contact) IsLinked(applicationID string) bool {
+	return slices.Contains(c.ApplicationIDs, applicationID)
+}
+
+// Link links the contact to an application, reporting whether it wasn't linked yet
+func (c *Contact) Link(applicationID string) bool {
+	if c.IsLinked(applicationID) {
+		return false
+	}
+	c.ApplicationIDs = append(c.ApplicationIDs, applicationID)
+	c.UpdatedAt = time.Now()
+	return true
+}
+
+// Unlink removes the link between the contact and an application, reporting whether
+// they were linked
+func (c *Contact) Unlink(applicationID string) bool {
+	i := slices.Index(c.ApplicationIDs, applicationID)
+	if i < 0 {
+		return false
+	}
+	c.ApplicationIDs = slices.Delete(c.ApplicationIDs, i, i+1)
+	c.UpdatedAt = time.Now()
+	return true
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// DateLayout is the format of dates without a time, such as "2024-03-15"
+const DateLayout = "2006-01-02"
+
+// ParseDate parses a date such as "2024-03-15", taken as midnight UTC, or a full
+// RFC 3339 timestamp
+func ParseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(DateLayout, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("dates must look like 2024-03-15 or 2024-03-15T09:30:00Z")
+	}
+	return t, nil
+}
+
+// SameDay reports whether a and b fall on the same calendar day in UTC
+func SameDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// SetAppliedAt backdates when the application was sent. An application still in the
+// status it was sent with entered that status when it was sent, so its status change
+// moves along with it.
+func (a *Application) SetAppliedAt(appliedAt time.Time) {
+	if a.Status == ApplicationStatus.Applied && (a.StatusChangedAt.IsZero() || a.StatusChangedAt.Equal(a.AppliedAt)) {
+		a.StatusChangedAt = appliedAt
+	}
+	a.AppliedAt = appliedAt
+}
+
+// ValidateDates checks the application wasn't sent or posted in the future and that
+// its posting doesn't close before it opened; a day of leeway allows for time zones
+func (a Application) ValidateDates(now time.Time) error {
+	latest := now.Add(24 * time.Hour)
+	if a.AppliedAt.After(latest) {
+		return errors.New("appliedAt must not be in the future")
+	}
+	if a.PostedAt != nil && a.PostedAt.After(latest) {
+		return errors.New("postedAt must not be in the future")
+	}
+	if a.PostedAt != nil && a.ClosingAt != nil && a.ClosingAt.Before(*a.PostedAt) {
+		return errors.New("closingAt must not be before postedAt")
+	}
+	return nil
+}
+
+// ActivityAt returns when the application last saw activity, falling back to its last
+// update for applications from before activity was recorded
+func (a Application) ActivityAt() time.Time {
+	if a.LastActivityAt.IsZero() {
+		return a.UpdatedAt
+	}
+	return a.LastActivityAt
+}
+
+// SortByApplied orders applications by when they were sent, most recent first
+func SortByApplied(applications []Application) {
+	sort.SliceStable(applications, func(i, j int) bool {
+		return applications[i].AppliedAt.After(applications[j].AppliedAt)
+	})
+}
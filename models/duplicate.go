@@ -0,0 +1,202 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// companySuffixes are legal-form words ignored when comparing company names
+var companySuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "llc": true, "ltd": true, "limited": true,
+	"corp": true, "corporation": true, "co": true, "company": true,
+	"gmbh": true, "ag": true, "plc": true, "sa": true, "bv": true,
+}
+
+// positionAbbreviations expands common abbreviations in job titles
+var positionAbbreviations = map[string]string{
+	"sr":   "senior",
+	"jr":   "junior",
+	"eng":  "engineer",
+	"engr": "engineer",
+	"dev":  "developer",
+	"mgr":  "manager",
+	"swe":  "software engineer",
+}
+
+// words splits s into lowercase alphanumeric words
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// NormalizeCompany reduces a company name to a comparable form, ignoring case,
+// punctuation and trailing legal forms such as "Inc." or "GmbH"
+func NormalizeCompany(name string) string {
+	parts := words(name)
+	for len(parts) > 1 && companySuffixes[parts[len(parts)-1]] {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, "")
+}
+
+// normalizePosition returns the words of a job title with abbreviations expanded
+func normalizePosition(position string) []string {
+	var result []string
+	for _, word := range words(position) {
+		if expanded, ok := positionAbbreviations[word]; ok {
+			result = append(result, strings.Fields(expanded)...)
+		} else {
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+// similarPositions reports whether two job titles likely describe the same role: they
+// share most of their words, or differ only by a few typos
+func similarPositions(a, b string) bool {
+	wordsA, wordsB := normalizePosition(a), normalizePosition(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return false
+	}
+
+	setA := make(map[string]bool, len(wordsA))
+	for _, word := range wordsA {
+		setA[word] = true
+	}
+	setB := make(map[string]bool, len(wordsB))
+	for _, word := range wordsB {
+		setB[word] = true
+	}
+	shared := 0
+	for word := range setA {
+		if setB[word] {
+			shared++
+		}
+	}
+	if float64(2*shared)/float64(len(setA)+len(setB)) >= 0.8 {
+		return true
+	}
+
+	joinedA, joinedB := strings.Join(wordsA, " "), strings.Join(wordsB, " ")
+	longest := max(len([]rune(joinedA)), len([]rune(joinedB)))
+	return 1-float64(editDistance(joinedA, joinedB))/float64(longest) >= 0.85
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// postingKey reduces a job posting URL to its host and path, ignoring the scheme, a
+// leading "www.", the query string and trailing slashes; URLs without a path give ""
+func postingKey(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	path := strings.TrimRight(u.Path, "/")
+	if path == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	return host + path
+}
+
+// IsLikelyDuplicate reports whether two applications probably track the same job: they
+// link to the same posting, or name the same company with a similar position
+func IsLikelyDuplicate(a, b Application) bool {
+	if key := postingKey(a.URL); key != "" && key == postingKey(b.URL) {
+		return true
+	}
+	company := NormalizeCompany(a.Company)
+	return company != "" && company == NormalizeCompany(b.Company) && similarPositions(a.Position, b.Position)
+}
+
+// Merge folds a duplicate into the application: tags are combined, a different
+// description is appended, a missing URL, compensation, offer, location, work mode,
+// time zone or custom field is filled in and the earliest creation and applied times
+// are kept. The application's own status wins.
+func (a *Application) Merge(duplicate Application) {
+	for _, tag := range duplicate.Tags {
+		a.AddTag(tag)
+	}
+
+	description := strings.TrimSpace(duplicate.Description)
+	switch {
+	case description == "" || strings.Contains(a.Description, description):
+	case strings.TrimSpace(a.Description) == "":
+		a.Description = duplicate.Description
+	default:
+		a.Description = strings.TrimRight(a.Description, "\n") + "\n\n" + description
+	}
+
+	if a.URL == "" {
+		a.URL = duplicate.URL
+	}
+	if a.Compensation == nil {
+		a.Compensation = duplicate.Compensation
+	}
+	if a.Offer == nil {
+		a.Offer = duplicate.Offer
+	}
+	if a.Location == nil {
+		a.Location = duplicate.Location
+	}
+	if a.WorkMode == "" {
+		a.WorkMode = duplicate.WorkMode
+	}
+	if a.TimeZone == nil {
+		a.TimeZone = duplicate.TimeZone
+	}
+	if duplicate.CreatedAt.Before(a.CreatedAt) {
+		a.CreatedAt = duplicate.CreatedAt
+	}
+	if !duplicate.AppliedAt.IsZero() && duplicate.AppliedAt.Before(a.AppliedAt) {
+		a.AppliedAt = duplicate.AppliedAt
+	}
+	if a.PostedAt == nil {
+		a.PostedAt = duplicate.PostedAt
+	}
+	if a.ClosingAt == nil {
+		a.ClosingAt = duplicate.ClosingAt
+	}
+	for key, value := range duplicate.Fields {
+		if a.Fields[key] == "" {
+			if a.Fields == nil {
+				a.Fields = map[string]string{}
+			}
+			a.Fields[key] = value
+		}
+	}
+	a.UpdatedAt = time.Now()
+	a.RecordActivity()
+}
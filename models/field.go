@@ -0,0 +1,280 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Custom field types
+const (
+	FieldText   = "text"
+	FieldNumber = "number"
+	FieldDate   = "date"
+	FieldSelect = "select"
+	FieldBool   = "bool"
+)
+
+// FieldTypes lists the types of custom fields
+var FieldTypes = []string{FieldText, FieldNumber, FieldDate, FieldSelect, FieldBool}
+
+// Bounds of custom field definitions and values
+const (
+	MaxFieldNameLength   = 50
+	MaxFieldOptions      = 50
+	MaxFieldOptionLength = 100
+	MaxFieldValueLength  = 500
+)
+
+// FieldDefinition is a custom field a workspace adds to its applications, such as a
+// referral bonus or whether the job sponsors visas. Applications keep their values
+// under the definition's Key, which is derived from its name.
+type FieldDefinition struct {
+	ID          string `json:"id"`
+	WorkspaceID string `json:"workspaceId"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	// Options are the choices of select fields
+	Options   []string  `json:"options,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// FieldKey derives the key of a custom field from its name, such as "visa_sponsorship"
+// for "Visa sponsorship"
+func FieldKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteByte('_')
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// NewFieldDefinition creates a custom field definition, checking its name, type and options
+func NewFieldDefinition(workspaceID, name, fieldType string, options []string) (*FieldDefinition, error) {
+	definition := &FieldDefinition{
+		ID:          generateID(),
+		WorkspaceID: workspaceID,
+		Name:        strings.TrimSpace(name),
+		Type:        fieldType,
+		CreatedAt:   time.Now(),
+	}
+	definition.Key = FieldKey(definition.Name)
+
+	if definition.Key == "" {
+		return nil, errors.New("name must contain letters or digits")
+	}
+	if len(definition.Name) > MaxFieldNameLength {
+		return nil, fmt.Errorf("name must not exceed %d characters", MaxFieldNameLength)
+	}
+	switch fieldType {
+	case FieldText, FieldNumber, FieldDate, FieldBool:
+		if len(options) > 0 {
+			return nil, errors.New("only select fields have options")
+		}
+	case FieldSelect:
+		for _, option := range options {
+			option = strings.TrimSpace(option)
+			if option == "" || definition.option(option) != "" {
+				continue
+			}
+			if len(option) > MaxFieldOptionLength {
+				return nil, fmt.Errorf("options must not exceed %d characters", MaxFieldOptionLength)
+			}
+			definition.Options = append(definition.Options, option)
+		}
+		if len(definition.Options) == 0 {
+			return nil, errors.New("select fields need at least one option")
+		}
+		if len(definition.Options) > MaxFieldOptions {
+			return nil, fmt.Errorf("a select field can have at most %d options", MaxFieldOptions)
+		}
+	default:
+		return nil, fmt.Errorf("field type must be one of: %s", strings.Join(FieldTypes, ", "))
+	}
+	return definition, nil
+}
+
+// option returns the option of a select field matching value, ignoring case, or ""
+func (d FieldDefinition) option(value string) string {
+	for _, option := range d.Options {
+		if strings.EqualFold(option, value) {
+			return option
+		}
+	}
+	return ""
+}
+
+// NormalizeValue checks a value of the field and returns it in the form it is stored:
+// numbers without redundant digits, dates as "2024-03-15", booleans as "true" or
+// "false" and select values as the option they match. An empty value stays empty.
+func (d FieldDefinition) NormalizeValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	switch d.Type {
+	case FieldNumber:
+		number, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return "", fmt.Errorf("%s must be a number", d.Name)
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case FieldDate:
+		date, err := ParseDate(value)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", d.Name, err)
+		}
+		return date.Format(DateLayout), nil
+	case FieldBool:
+		switch strings.ToLower(value) {
+		case "true", "yes", "on", "1":
+			return "true", nil
+		case "false", "no", "off", "0":
+			return "false", nil
+		}
+		return "", fmt.Errorf("%s must be yes or no", d.Name)
+	case FieldSelect:
+		if option := d.option(value); option != "" {
+			return option, nil
+		}
+		return "", fmt.Errorf("%s must be one of: %s", d.Name, strings.Join(d.Options, ", "))
+	}
+	if len(value) > MaxFieldValueLength {
+		return "", fmt.Errorf("%s must not exceed %d characters", d.Name, MaxFieldValueLength)
+	}
+	return value, nil
+}
+
+// Display formats a stored value of the field for people, such as "Yes" for booleans
+func (d FieldDefinition) Display(value string) string {
+	switch d.Type {
+	case FieldBool:
+		if value == "true" {
+			return "Yes"
+		}
+		return "No"
+	case FieldDate:
+		if date, err := time.Parse(DateLayout, value); err == nil {
+			return date.Format("January 2, 2006")
+		}
+	}
+	return value
+}
+
+// NormalizeFields checks custom field values against a workspace's definitions and
+// returns them as they are stored; empty values are dropped, and nil is returned when
+// none are left
+func NormalizeFields(definitions []FieldDefinition, values map[string]string) (map[string]string, error) {
+	fields := map[string]string{}
+	for key, value := range values {
+		definition, ok := FindField(definitions, key)
+		if !ok {
+			return nil, fmt.Errorf("unknown custom field %q", key)
+		}
+		normalized, err := definition.NormalizeValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if normalized != "" {
+			fields[key] = normalized
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// FindField returns the definition with the given key
+func FindField(definitions []FieldDefinition, key string) (FieldDefinition, bool) {
+	for _, definition := range definitions {
+		if definition.Key == key {
+			return definition, true
+		}
+	}
+	return FieldDefinition{}, false
+}
+
+// FieldFilter selects applications by the value of a custom field: text fields match
+// values containing Value, ignoring case, and other fields match it exactly
+type FieldFilter struct {
+	Field FieldDefinition
+	// Value is normalized for the field, except for text fields
+	Value string
+}
+
+// NewFieldFilter creates a filter on a custom field, checking the value fits the field
+func NewFieldFilter(definition FieldDefinition, value string) (FieldFilter, error) {
+	if definition.Type == FieldText {
+		return FieldFilter{Field: definition, Value: strings.TrimSpace(value)}, nil
+	}
+	normalized, err := definition.NormalizeValue(value)
+	if err != nil {
+		return FieldFilter{}, err
+	}
+	return FieldFilter{Field: definition, Value: normalized}, nil
+}
+
+// Matches reports whether the application passes the filter
+func (f FieldFilter) Matches(app Application) bool {
+	value := app.Fields[f.Field.Key]
+	if f.Field.Type == FieldText {
+		return value != "" && strings.Contains(strings.ToLower(value), strings.ToLower(f.Value))
+	}
+	return value == f.Value
+}
+
+// FilterByFields returns the applications all the filters match
+func FilterByFields(applications []Application, filters []FieldFilter) []Application {
+	if len(filters) == 0 {
+		return applications
+	}
+	result := []Application{}
+	for _, app := range applications {
+		matches := true
+		for _, filter := range filters {
+			if !filter.Matches(app) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result = append(result, app)
+		}
+	}
+	return result
+}
+
+// CustomField is a custom field of an application with its value, for display
+type CustomField struct {
+	FieldDefinition
+	Value string
+}
+
+// CustomFields pairs the workspace's field definitions with the application's values,
+// in the order of the definitions; with skipEmpty fields without a value are left out
+func (a Application) CustomFields(definitions []FieldDefinition, skipEmpty bool) []CustomField {
+	fields := []CustomField{}
+	for _, definition := range definitions {
+		value := a.Fields[definition.Key]
+		if skipEmpty && value == "" {
+			continue
+		}
+		fields = append(fields, CustomField{FieldDefinition: definition, Value: value})
+	}
+	return fields
+}
+
+// DisplayValue formats the value of the field for people
+func (f CustomField) DisplayValue() string {
+	return f.Display(f.Value)
+}
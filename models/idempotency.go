@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is the stored response to the first request made with an idempotency key
+type IdempotencyRecord struct {
+	Key         string            `json:"key"`
+	UserID      string            `json:"userId"`
+	RequestHash string            `json:"requestHash"`
+	Status      int               `json:"status"`
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	ExpiresAt   time.Time         `json:"expiresAt"`
+}
+
+// Expired reports whether the record can no longer be replayed at the given time
+func (r *IdempotencyRecord) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,221 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Interview rounds
+const (
+	InterviewPhoneScreen = "phone_screen"
+	InterviewTechnical   = "technical"
+	InterviewTakeHome    = "take_home"
+	InterviewBehavioral  = "behavioral"
+	InterviewOnsite      = "onsite"
+	InterviewFinal       = "final"
+	InterviewOther       = "other"
+)
+
+// InterviewRounds lists the kinds of interview rounds in their usual order
+var InterviewRounds = []string{
+	InterviewPhoneScreen, InterviewTechnical, InterviewTakeHome, InterviewBehavioral,
+	InterviewOnsite, InterviewFinal, InterviewOther,
+}
+
+// interviewRoundNames describes interview rounds for display
+var interviewRoundNames = map[string]string{
+	InterviewPhoneScreen: "Phone Screen",
+	InterviewTechnical:   "Technical",
+	InterviewTakeHome:    "Take-Home",
+	InterviewBehavioral:  "Behavioral",
+	InterviewOnsite:      "Onsite",
+	InterviewFinal:       "Final",
+	InterviewOther:       "Other",
+}
+
+// InterviewRoundName describes an interview round for display
+func InterviewRoundName(round string) string {
+	if name, ok := interviewRoundNames[round]; ok {
+		return name
+	}
+	return round
+}
+
+// Interview outcomes
+const (
+	InterviewPending   = "pending"
+	InterviewPassed    = "passed"
+	InterviewFailed    = "failed"
+	InterviewCancelled = "cancelled"
+)
+
+// InterviewOutcomes lists the possible outcomes of an interview
+var InterviewOutcomes = []string{InterviewPending, InterviewPassed, InterviewFailed, InterviewCancelled}
+
+const (
+	// MaxInterviewers bounds the number of interviewers of an interview
+	MaxInterviewers = 20
+	// DefaultInterviewLength is the length of an interview created without an end time
+	DefaultInterviewLength = time.Hour
+)
+
+// InterviewDetails are the fields of an interview set by the user
+type InterviewDetails struct {
+	Round        string    `json:"round"`
+	StartsAt     time.Time `json:"startsAt"`
+	EndsAt       time.Time `json:"endsAt"`
+	TimeZone     string    `json:"timeZone"`
+	Location     string    `json:"location,omitempty"`
+	VideoURL     string    `json:"videoUrl,omitempty"`
+	Interviewers []string  `json:"interviewers"`
+	Outcome      string    `json:"outcome"`
+	PrepNotes    string    `json:"prepNotes,omitempty"`
+}
+
+// Interview is a scheduled interview round for an application
+type Interview struct {
+	ID            string `json:"id"`
+	WorkspaceID   string `json:"workspaceId"`
+	ApplicationID string `json:"applicationId"`
+	InterviewDetails
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// UpcomingInterview is an interview with the application it belongs to, for the home page
+type UpcomingInterview struct {
+	Interview
+	Company  string `json:"company"`
+	Position string `json:"position"`
+}
+
+// NewInterview creates an interview for an application, validating its details
+func NewInterview(workspaceID, applicationID string, details InterviewDetails) (*Interview, error) {
+	if err := details.normalize(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Interview{
+		ID:               generateID(),
+		WorkspaceID:      workspaceID,
+		ApplicationID:    applicationID,
+		InterviewDetails: details,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}, nil
+}
+
+// Update replaces the details of the interview, validating them
+func (i *Interview) Update(details InterviewDetails) error {
+	if err := details.normalize(); err != nil {
+		return err
+	}
+	i.InterviewDetails = details
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// normalize trims and validates the details, filling in the default outcome, time zone
+// and end time
+func (d *InterviewDetails) normalize() error {
+	d.Round = strings.TrimSpace(d.Round)
+	d.TimeZone = strings.TrimSpace(d.TimeZone)
+	d.Location = strings.TrimSpace(d.Location)
+	d.VideoURL = strings.TrimSpace(d.VideoURL)
+	d.Outcome = strings.TrimSpace(d.Outcome)
+	d.PrepNotes = strings.TrimSpace(d.PrepNotes)
+
+	if !slices.Contains(InterviewRounds, d.Round) {
+		return fmt.Errorf("round must be one of: %s", strings.Join(InterviewRounds, ", "))
+	}
+	if d.Outcome == "" {
+		d.Outcome = InterviewPending
+	}
+	if !slices.Contains(InterviewOutcomes, d.Outcome) {
+		return fmt.Errorf("outcome must be one of: %s", strings.Join(InterviewOutcomes, ", "))
+	}
+
+	if d.StartsAt.IsZero() {
+		return errors.New("startsAt is required")
+	}
+	if d.EndsAt.IsZero() {
+		d.EndsAt = d.StartsAt.Add(DefaultInterviewLength)
+	}
+	if !d.EndsAt.After(d.StartsAt) {
+		return errors.New("endsAt must be after startsAt")
+	}
+	if d.TimeZone == "" {
+		d.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(d.TimeZone); err != nil {
+		return fmt.Errorf("unknown time zone: %q", d.TimeZone)
+	}
+
+	if len(d.Location) > 200 {
+		return errors.New("location must not exceed 200 characters")
+	}
+	if d.VideoURL != "" {
+		u, err := url.Parse(d.VideoURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("videoUrl must be an http or https URL")
+		}
+	}
+	if len(d.PrepNotes) > MaxNoteLength {
+		return fmt.Errorf("prepNotes must not exceed %d characters", MaxNoteLength)
+	}
+
+	interviewers := []string{}
+	for _, name := range d.Interviewers {
+		if name = strings.TrimSpace(name); name != "" {
+			interviewers = append(interviewers, name)
+		}
+	}
+	if len(interviewers) > MaxInterviewers {
+		return fmt.Errorf("an interview can have at most %d interviewers", MaxInterviewers)
+	}
+	d.Interviewers = interviewers
+	return nil
+}
+
+// RoundName describes the round of the interview for display
+func (d InterviewDetails) RoundName() string {
+	return InterviewRoundName(d.Round)
+}
+
+// LocalStart returns the start of the interview in its time zone
+func (i Interview) LocalStart() time.Time {
+	loc, err := time.LoadLocation(i.TimeZone)
+	if err != nil {
+		return i.StartsAt
+	}
+	return i.StartsAt.In(loc)
+}
+
+// LocalEnd returns the end of the interview in its time zone
+func (i Interview) LocalEnd() time.Time {
+	loc, err := time.LoadLocation(i.TimeZone)
+	if err != nil {
+		return i.EndsAt
+	}
+	return i.EndsAt.In(loc)
+}
+
+// Upcoming reports whether the interview is still pending and hasn't ended by now
+func (i Interview) Upcoming(now time.Time) bool {
+	return i.Outcome == InterviewPending && i.EndsAt.After(now)
+}
+
+// ProgressForInterview moves an application that was only applied to in progress once
+// an interview is scheduled, which counts as activity, reporting whether the status changed
+func (a *Application) ProgressForInterview() bool {
+	if a.Status != ApplicationStatus.Applied {
+		return false
+	}
+	a.UpdateStatus(ApplicationStatus.InProgress)
+	a.RecordActivity()
+	return true
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Work modes of a job
+const (
+	WorkModeRemote = "remote"
+	WorkModeHybrid = "hybrid"
+	WorkModeOnsite = "onsite"
+)
+
+// Bounds of UTC offsets, in hours
+const (
+	MinUTCOffset = -12
+	MaxUTCOffset = 14
+)
+
+// IsValidWorkMode reports whether mode is one of the known work modes
+func IsValidWorkMode(mode string) bool {
+	switch mode {
+	case WorkModeRemote, WorkModeHybrid, WorkModeOnsite:
+		return true
+	}
+	return false
+}
+
+// WorkModeName returns the display name of a work mode
+func WorkModeName(mode string) string {
+	switch mode {
+	case WorkModeRemote:
+		return "Remote"
+	case WorkModeHybrid:
+		return "Hybrid"
+	case WorkModeOnsite:
+		return "On-site"
+	}
+	return mode
+}
+
+// workModeTags maps the tags that used to stand for a work mode to the mode
+var workModeTags = map[string]string{
+	"remote":       WorkModeRemote,
+	"fully remote": WorkModeRemote,
+	"hybrid":       WorkModeHybrid,
+	"onsite":       WorkModeOnsite,
+	"on-site":      WorkModeOnsite,
+	"on site":      WorkModeOnsite,
+	"in office":    WorkModeOnsite,
+}
+
+// Location is where a job is based
+type Location struct {
+	City    string `json:"city,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+// IsZero reports whether nothing about the location is known
+func (l Location) IsZero() bool {
+	return l == Location{}
+}
+
+// Normalize trims the parts of the location
+func (l *Location) Normalize() {
+	l.City = strings.TrimSpace(l.City)
+	l.Region = strings.TrimSpace(l.Region)
+	l.Country = strings.TrimSpace(l.Country)
+}
+
+// String joins the known parts of the location, such as "Berlin, Germany"
+func (l Location) String() string {
+	var parts []string
+	for _, part := range []string{l.City, l.Region, l.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// TimeZoneRequirement is the range of UTC offsets, in hours, a job expects people to
+// work from, such as -5 to 1 for "US Eastern to Central European time"
+type TimeZoneRequirement struct {
+	MinOffset float64 `json:"minOffset"`
+	MaxOffset float64 `json:"maxOffset"`
+}
+
+// Validate checks the offsets are real and in order
+func (t TimeZoneRequirement) Validate() error {
+	if t.MinOffset < MinUTCOffset || t.MaxOffset > MaxUTCOffset {
+		return fmt.Errorf("UTC offsets must be between %d and +%d hours", MinUTCOffset, MaxUTCOffset)
+	}
+	if t.MinOffset > t.MaxOffset {
+		return errors.New("minOffset must not exceed maxOffset")
+	}
+	return nil
+}
+
+// Includes reports whether someone at the UTC offset meets the requirement
+func (t TimeZoneRequirement) Includes(offset float64) bool {
+	return offset >= t.MinOffset && offset <= t.MaxOffset
+}
+
+// formatOffset formats a UTC offset in hours, such as "UTC-5" or "UTC+5:30"
+func formatOffset(offset float64) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	hours := int(offset)
+	if minutes := int((offset - float64(hours)) * 60); minutes != 0 {
+		return fmt.Sprintf("UTC%s%d:%02d", sign, hours, minutes)
+	}
+	return fmt.Sprintf("UTC%s%d", sign, hours)
+}
+
+// String describes the requirement, such as "UTC-5 to UTC+1"
+func (t TimeZoneRequirement) String() string {
+	if t.MinOffset == t.MaxOffset {
+		return formatOffset(t.MinOffset)
+	}
+	return formatOffset(t.MinOffset) + " to " + formatOffset(t.MaxOffset)
+}
+
+// WorkModeName returns the display name of the application's work mode
+func (a Application) WorkModeName() string {
+	return WorkModeName(a.WorkMode)
+}
+
+// SetWorkModeFromTags sets the work mode from a tag that stands for one, such as
+// "remote", keeping the tag. Applications that already have a work mode, or whose tags
+// name different modes, are left alone. It reports whether the application changed.
+func (a *Application) SetWorkModeFromTags() bool {
+	if a.WorkMode != "" {
+		return false
+	}
+	mode := ""
+	for _, tag := range a.Tags {
+		if m, ok := workModeTags[tag]; ok {
+			if mode != "" && mode != m {
+				return false
+			}
+			mode = m
+		}
+	}
+	if mode == "" {
+		return false
+	}
+	a.WorkMode = mode
+	return true
+}
+
+// LocationFilter selects applications by work mode, place and time zone; empty fields
+// match every application
+type LocationFilter struct {
+	WorkMode string
+	// Place matches the city, region or country, ignoring case
+	Place string
+	// UTCOffset matches applications whose time zone requirement includes it, and
+	// those without one
+	UTCOffset *float64
+}
+
+// IsZero reports whether the filter selects every application
+func (f LocationFilter) IsZero() bool {
+	return f.WorkMode == "" && f.Place == "" && f.UTCOffset == nil
+}
+
+// Matches reports whether the application passes the filter
+func (f LocationFilter) Matches(app Application) bool {
+	if f.WorkMode != "" && app.WorkMode != f.WorkMode {
+		return false
+	}
+	if f.Place != "" {
+		if app.Location == nil || !strings.Contains(strings.ToLower(app.Location.String()), strings.ToLower(f.Place)) {
+			return false
+		}
+	}
+	if f.UTCOffset != nil && app.TimeZone != nil && !app.TimeZone.Includes(*f.UTCOffset) {
+		return false
+	}
+	return true
+}
+
+// FilterByLocation returns the applications the filter matches
+func FilterByLocation(applications []Application, filter LocationFilter) []Application {
+	if filter.IsZero() {
+		return applications
+	}
+	result := []Application{}
+	for _, app := range applications {
+		if filter.Matches(app) {
+			result = append(result, app)
+		}
+	}
+	return result
+}
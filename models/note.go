@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// MaxNoteLength bounds the length of a note body
+const MaxNoteLength = 10000
+
+// Note is a timestamped entry kept with an application, such as a recruiter call or interview notes
+type Note struct {
+	ID            string     `json:"id"`
+	WorkspaceID   string     `json:"workspaceId"`
+	ApplicationID string     `json:"applicationId"`
+	AuthorID      string     `json:"authorId"`
+	AuthorName    string     `json:"authorName"`
+	Body          string     `json:"body"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	EditedAt      *time.Time `json:"editedAt,omitempty"`
+}
+
+// NewNote creates a note by author on an application
+func NewNote(workspaceID, applicationID string, author *User, body string) *Note {
+	return &Note{
+		ID:            generateID(),
+		WorkspaceID:   workspaceID,
+		ApplicationID: applicationID,
+		AuthorID:      author.ID,
+		AuthorName:    author.Username,
+		Body:          body,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// Edit replaces the body of the note and records when it was edited
+func (n *Note) Edit(body string) {
+	now := time.Now()
+	n.Body = body
+	n.EditedAt = &now
+}
+
+// SystemAuthorName is the author shown on notes the tracker adds by itself
+const SystemAuthorName = "Application Tracker"
+
+// NewSystemNote creates a note the tracker adds to an application's history by itself,
+// such as when it changes the status; it has no author ID
+func NewSystemNote(workspaceID, applicationID, body string) *Note {
+	return &Note{
+		ID:            generateID(),
+		WorkspaceID:   workspaceID,
+		ApplicationID: applicationID,
+		AuthorName:    SystemAuthorName,
+		Body:          body,
+		CreatedAt:     time.Now(),
+	}
+}
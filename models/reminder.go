@@ -0,0 +1,250 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxReminderMessageLength bounds the length of a reminder message
+const MaxReminderMessageLength = 500
+
+// MaxReminderRuleDays bounds how long after a status change a rule can remind
+const MaxReminderRuleDays = 365
+
+// Reminder states
+const (
+	ReminderPending = "pending"
+	ReminderFired   = "fired"
+	ReminderDone    = "done"
+)
+
+// Reminder is a follow-up due on an application. The scheduler fires it into the
+// workspace's notifications once it is due; snoozing moves it later and it fires again.
+type Reminder struct {
+	ID            string     `json:"id"`
+	WorkspaceID   string     `json:"workspaceId"`
+	ApplicationID string     `json:"applicationId"`
+	Message       string     `json:"message"`
+	DueAt         time.Time  `json:"dueAt"`
+	Done          bool       `json:"done"`
+	FiredAt       *time.Time `json:"firedAt,omitempty"`
+	// RuleID and StatusSince identify reminders created by a reminder rule and the
+	// status change they were created for, so a rule reminds once per status change
+	RuleID      string     `json:"ruleId,omitempty"`
+	StatusSince *time.Time `json:"statusSince,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// ReminderRule creates a reminder for applications that have kept a status for a
+// number of days, such as "follow up 7 days after applying if nothing changed"
+type ReminderRule struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspaceId"`
+	Status      string    `json:"status"`
+	Days        int       `json:"days"`
+	Message     string    `json:"message,omitempty"`
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Notification is shown in the header of every member of a workspace until it is read
+type Notification struct {
+	ID            string     `json:"id"`
+	WorkspaceID   string     `json:"workspaceId"`
+	ApplicationID string     `json:"applicationId"`
+	ReminderID    string     `json:"reminderId"`
+	Subject       string     `json:"subject"`
+	Message       string     `json:"message"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	ReadAt        *time.Time `json:"readAt,omitempty"`
+}
+
+// validateReminderMessage trims a reminder message and checks its length
+func validateReminderMessage(message string) (string, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return "", errors.New("message is required")
+	}
+	if len(message) > MaxReminderMessageLength {
+		return "", fmt.Errorf("message must not exceed %d characters", MaxReminderMessageLength)
+	}
+	return message, nil
+}
+
+// NewReminder creates a reminder on an application due at dueAt
+func NewReminder(workspaceID, applicationID, message string, dueAt time.Time) (*Reminder, error) {
+	message, err := validateReminderMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if dueAt.IsZero() {
+		return nil, errors.New("dueAt is required")
+	}
+	now := time.Now()
+	return &Reminder{
+		ID:            generateID(),
+		WorkspaceID:   workspaceID,
+		ApplicationID: applicationID,
+		Message:       message,
+		DueAt:         dueAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Update replaces the message, due time and done flag of the reminder; moving the due
+// time lets a fired reminder fire again
+func (r *Reminder) Update(message string, dueAt time.Time, done bool) error {
+	message, err := validateReminderMessage(message)
+	if err != nil {
+		return err
+	}
+	if dueAt.IsZero() {
+		return errors.New("dueAt is required")
+	}
+	if !dueAt.Equal(r.DueAt) {
+		r.FiredAt = nil
+	}
+	r.Message = message
+	r.DueAt = dueAt
+	r.Done = done
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Snooze postpones the reminder until a later time, when it fires again
+func (r *Reminder) Snooze(until, now time.Time) error {
+	if !until.After(now) {
+		return errors.New("a reminder can only be snoozed until a later time")
+	}
+	r.DueAt = until
+	r.FiredAt = nil
+	r.Done = false
+	r.UpdatedAt = now
+	return nil
+}
+
+// State returns whether the reminder is pending, has fired or is done
+func (r Reminder) State() string {
+	switch {
+	case r.Done:
+		return ReminderDone
+	case r.FiredAt != nil:
+		return ReminderFired
+	default:
+		return ReminderPending
+	}
+}
+
+// IsDue reports whether the reminder should fire at now
+func (r Reminder) IsDue(now time.Time) bool {
+	return r.State() == ReminderPending && !r.DueAt.After(now)
+}
+
+// Fire marks the reminder fired and returns the notification announcing it
+func (r *Reminder) Fire(subject string, now time.Time) Notification {
+	r.FiredAt = &now
+	return Notification{
+		ID:            generateID(),
+		WorkspaceID:   r.WorkspaceID,
+		ApplicationID: r.ApplicationID,
+		ReminderID:    r.ID,
+		Subject:       subject,
+		Message:       r.Message,
+		CreatedAt:     now,
+	}
+}
+
+// NewReminderRule creates an enabled reminder rule
+func NewReminderRule(workspaceID, status string, days int, message string) (*ReminderRule, error) {
+	rule := &ReminderRule{
+		ID:          generateID(),
+		WorkspaceID: workspaceID,
+		Enabled:     true,
+		CreatedAt:   time.Now(),
+	}
+	if err := rule.Update(status, days, message, true); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DefaultReminderRules returns the rules a workspace created at createdAt starts with.
+// They get fixed IDs and creation times, so they are the same however often they are built.
+func DefaultReminderRules(workspaceID string, createdAt time.Time) []ReminderRule {
+	rule, _ := NewReminderRule(workspaceID, ApplicationStatus.Applied, 7, "")
+	rule.ID = "default-" + workspaceID
+	rule.CreatedAt = createdAt
+	return []ReminderRule{*rule}
+}
+
+// Update replaces the settings of the rule, validating them
+func (r *ReminderRule) Update(status string, days int, message string, enabled bool) error {
+	if !IsValidStatus(status) {
+		return errors.New("status must be a valid application status")
+	}
+	if days < 1 || days > MaxReminderRuleDays {
+		return fmt.Errorf("days must be between 1 and %d", MaxReminderRuleDays)
+	}
+	message = strings.TrimSpace(message)
+	if len(message) > MaxReminderMessageLength {
+		return fmt.Errorf("message must not exceed %d characters", MaxReminderMessageLength)
+	}
+	r.Status = status
+	r.Days = days
+	r.Message = message
+	r.Enabled = enabled
+	return nil
+}
+
+// StatusName returns the display name of the status the rule applies to
+func (r ReminderRule) StatusName() string {
+	return StatusName(r.Status)
+}
+
+// ReminderMessage returns the message of the rule's reminders
+func (r ReminderRule) ReminderMessage() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return fmt.Sprintf("No change for %d days since the status became %s - time to follow up", r.Days, StatusName(r.Status))
+}
+
+// ReminderFor returns the reminder the rule creates for an application at now, or nil
+// if the application hasn't kept the rule's status for its days yet. Reminders that
+// fell due before the rule was created are skipped so new rules don't flood old
+// applications with overdue reminders.
+func (r ReminderRule) ReminderFor(app Application, now time.Time) *Reminder {
+	if !r.Enabled || app.Status != r.Status {
+		return nil
+	}
+	since := app.StatusSince()
+	due := since.AddDate(0, 0, r.Days)
+	if due.After(now) || due.Before(r.CreatedAt) {
+		return nil
+	}
+	reminder, err := NewReminder(app.OwnerID, app.ID, r.ReminderMessage(), due)
+	if err != nil {
+		return nil
+	}
+	reminder.RuleID = r.ID
+	reminder.StatusSince = &since
+	return reminder
+}
+
+// IsFor reports whether the reminder was created by rule for the application's current
+// status
+func (r Reminder) IsFor(rule ReminderRule, app Application) bool {
+	return r.RuleID == rule.ID && r.ApplicationID == app.ID &&
+		r.StatusSince != nil && r.StatusSince.Equal(app.StatusSince())
+}
+
+// MarkRead records when the notification was read
+func (n *Notification) MarkRead(now time.Time) {
+	if n.ReadAt == nil {
+		n.ReadAt = &now
+	}
+}
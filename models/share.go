@@ -0,0 +1,75 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ShareFilter restricts which applications a sharing link exposes
+type ShareFilter struct {
+	Status string   `json:"status,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// Share is a read-only sharing link to a filtered view of a user's applications
+type Share struct {
+	ID        string      `json:"id"`
+	OwnerID   string      `json:"ownerId"`
+	Name      string      `json:"name"`
+	Filter    ShareFilter `json:"filter"`
+	CreatedAt time.Time   `json:"createdAt"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+	RevokedAt *time.Time  `json:"revokedAt,omitempty"`
+}
+
+// NewShare creates a sharing link that expires at the given time
+func NewShare(ownerID, name string, filter ShareFilter, expiresAt time.Time) *Share {
+	return &Share{
+		ID:        generateID(),
+		OwnerID:   ownerID,
+		Name:      name,
+		Filter:    filter,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+}
+
+// Active reports whether the link can still be used at the given time
+func (s *Share) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Matches reports whether an application is visible through the filter; every tag must be present
+func (f ShareFilter) Matches(app Application) bool {
+	if f.Status != "" && app.Status != f.Status {
+		return false
+	}
+	for _, tag := range f.Tags {
+		found := false
+		for _, appTag := range app.Tags {
+			if strings.EqualFold(appTag, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Description summarizes the filter for display
+func (f ShareFilter) Description() string {
+	var parts []string
+	if f.Status != "" {
+		parts = append(parts, "status:"+f.Status)
+	}
+	for _, tag := range f.Tags {
+		parts = append(parts, "tag:"+tag)
+	}
+	if len(parts) == 0 {
+		return "all applications"
+	}
+	return strings.Join(parts, ", ")
+}
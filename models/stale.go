@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxStaleDays bounds the stale and ghosting thresholds
+const MaxStaleDays = 365
+
+// StaleSettings decide when the applications of a workspace need attention: an
+// application is stale once it has kept a status in Days without an update for that
+// many days, and is moved to ghosted once it has gone GhostAfterDays without one
+type StaleSettings struct {
+	WorkspaceID string         `json:"workspaceId"`
+	Days        map[string]int `json:"days"`
+	// GhostAfterDays turns on ghosting stale applications; 0 leaves them flagged
+	GhostAfterDays int `json:"ghostAfterDays"`
+}
+
+// DefaultStaleSettings returns the settings a workspace starts with: applications are
+// stale after three weeks applied or two weeks in progress, and never ghosted
+func DefaultStaleSettings(workspaceID string) StaleSettings {
+	return StaleSettings{
+		WorkspaceID: workspaceID,
+		Days: map[string]int{
+			ApplicationStatus.Applied:    21,
+			ApplicationStatus.InProgress: 14,
+		},
+	}
+}
+
+// Validate checks the thresholds; only open statuses can become stale, and ghosting
+// can't happen before an application is stale
+func (s StaleSettings) Validate() error {
+	for status, days := range s.Days {
+		if status != ApplicationStatus.Applied && status != ApplicationStatus.InProgress {
+			return fmt.Errorf("only applied and in_progress applications can become stale, not %q", status)
+		}
+		if days < 0 || days > MaxStaleDays {
+			return fmt.Errorf("days must be between 0 and %d", MaxStaleDays)
+		}
+		if s.GhostAfterDays > 0 && days > s.GhostAfterDays {
+			return errors.New("ghostAfterDays must not be shorter than the stale thresholds")
+		}
+	}
+	if s.GhostAfterDays < 0 || s.GhostAfterDays > MaxStaleDays {
+		return fmt.Errorf("ghostAfterDays must be between 0 and %d", MaxStaleDays)
+	}
+	return nil
+}
+
+// idleSince reports whether app has gone days without activity at now
+func idleSince(app Application, days int, now time.Time) bool {
+	return days > 0 && !app.ActivityAt().AddDate(0, 0, days).After(now)
+}
+
+// IsStale reports whether the settings make app stale at now; a status without a
+// threshold never does
+func (s StaleSettings) IsStale(app Application, now time.Time) bool {
+	return idleSince(app, s.Days[app.Status], now)
+}
+
+// ShouldGhost reports whether the settings move app to ghosted at now
+func (s StaleSettings) ShouldGhost(app Application, now time.Time) bool {
+	return s.GhostAfterDays > 0 && s.IsStale(app, now) && idleSince(app, s.GhostAfterDays, now)
+}
+
+// GhostReason explains why app is being moved to ghosted, for its history
+func (s StaleSettings) GhostReason(app Application) string {
+	return fmt.Sprintf("Marked as Ghosted automatically: no updates for %d days while %s.", s.GhostAfterDays, StatusName(app.Status))
+}
+
+// DaysFor returns the stale threshold for status, or 0 if applications with the status
+// never become stale
+func (s StaleSettings) DaysFor(status string) int {
+	return s.Days[status]
+}
@@ -0,0 +1,68 @@
+package models
+
+import "strings"
+
+// DefaultTagColor is the color of tags that have not been given one
+const DefaultTagColor = "blue"
+
+// TagColors are the colors a tag can be shown in
+var TagColors = []string{"blue", "gray", "red", "yellow", "green", "indigo", "purple", "pink"}
+
+// Tag summarizes a tag across the applications of a workspace
+type Tag struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	Count int    `json:"count"`
+}
+
+// TagColor is the color chosen for a tag in a workspace
+type TagColor struct {
+	WorkspaceID string `json:"workspaceId"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+}
+
+// IsValidTagColor reports whether color is one of the tag colors
+func IsValidTagColor(color string) bool {
+	for _, c := range TagColors {
+		if c == color {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTag lowercases a tag and collapses its whitespace, so "Remote", "remote"
+// and " remote " are the same tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// NormalizeTags normalizes a list of tags, dropping empty tags and duplicates
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := []string{}
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" && !containsTag(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// HasTag reports whether the application has the given tag
+func (a *Application) HasTag(tag string) bool {
+	return containsTag(a.Tags, NormalizeTag(tag))
+}
+
+// containsTag reports whether a list of normalized tags contains tag
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+)
+
+// Scopes that can be granted to an API token
+const (
+	ScopeApplicationsRead  = "applications:read"
+	ScopeApplicationsWrite = "applications:write"
+	ScopeAdmin             = "admin"
+)
+
+// ValidScopes lists every scope a token can be granted
+var ValidScopes = []string{ScopeApplicationsRead, ScopeApplicationsWrite, ScopeAdmin}
+
+// APIToken is a personal access token used by scripts and integrations; only a hash
+// of the secret is stored
+type APIToken struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"userId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	TokenHash  string     `json:"tokenHash,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// NewAPIToken creates a token record for the given secret hash
+func NewAPIToken(userID, name, prefix, tokenHash string, scopes []string, expiresAt *time.Time) *APIToken {
+	return &APIToken{
+		ID:        generateID(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		TokenHash: tokenHash,
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+}
+
+// IsValidScope reports whether scope is a known scope
+func IsValidScope(scope string) bool {
+	for _, s := range ValidScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the token grants scope; admin grants every scope
+// and applications:write implies applications:read
+func (t *APIToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+		if s == ScopeApplicationsWrite && scope == ScopeApplicationsRead {
+			return true
+		}
+	}
+	return false
+}
+
+// Active reports whether the token can still be used at the given time
+func (t *APIToken) Active(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
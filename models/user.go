@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// User represents a local user account
+type User struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"passwordHash"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Session represents a logged-in browser session; only a hash of the session token is stored
+type Session struct {
+	TokenHash string    `json:"tokenHash"`
+	UserID    string    `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// NewUser creates a new user with a normalized username
+func NewUser(username, passwordHash string) *User {
+	now := time.Now()
+	return &User{
+		ID:           generateID(),
+		Username:     NormalizeUsername(username),
+		PasswordHash: passwordHash,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// NormalizeUsername lowercases and trims a username so lookups are case-insensitive
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// Expired reports whether the session is no longer valid at the given time
+func (s *Session) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
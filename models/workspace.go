@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+)
+
+// Workspace roles, from least to most privileged
+const (
+	RoleViewer    = "viewer"
+	RoleCommenter = "commenter"
+	RoleEditor    = "editor"
+	RoleOwner     = "owner"
+)
+
+// MemberRoles lists the roles that can be granted to workspace members
+var MemberRoles = []string{RoleViewer, RoleCommenter, RoleEditor}
+
+// roleRank orders roles so a role includes every less privileged one
+var roleRank = map[string]int{
+	RoleViewer:    1,
+	RoleCommenter: 2,
+	RoleEditor:    3,
+	RoleOwner:     4,
+}
+
+// Workspace is the set of applications owned by one user, as seen by a member;
+// its ID is the owner's user ID
+type Workspace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// Can reports whether the workspace role includes the required role
+func (w *Workspace) Can(required string) bool {
+	return RoleAllows(w.Role, required)
+}
+
+// Membership grants a user a role in another user's workspace
+type Membership struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspaceId"`
+	UserID      string    `json:"userId"`
+	Username    string    `json:"username"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// NewMembership creates a membership of user in the workspace with the given role
+func NewMembership(workspaceID string, user *User, role string) *Membership {
+	now := time.Now()
+	return &Membership{
+		ID:          generateID(),
+		WorkspaceID: workspaceID,
+		UserID:      user.ID,
+		Username:    user.Username,
+		Role:        role,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// IsValidMemberRole reports whether role can be granted to a member
+func IsValidMemberRole(role string) bool {
+	for _, r := range MemberRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleAllows reports whether role includes the required role
+func RoleAllows(role, required string) bool {
+	return roleRank[required] > 0 && roleRank[role] >= roleRank[required]
+}
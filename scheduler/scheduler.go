@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"ApplicationTracker/metrics"
+	"ApplicationTracker/storage"
+)
+
+// Run fires due reminders and flags stale applications every interval until ctx is
+// done; it runs once immediately so reminders that fell due while the server was down
+// fire on startup
+func Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	slog.InfoContext(ctx, "scheduler started", "interval", interval)
+	for {
+		runOnce(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce creates and fires the reminders due at now and checks for stale
+// applications, logging rather than returning failures
+func runOnce(ctx context.Context, now time.Time) {
+	start := time.Now()
+	fired, remindersErr := storage.RunReminders(ctx, now)
+	if remindersErr != nil {
+		slog.ErrorContext(ctx, "failed to run reminders", "error", remindersErr)
+	}
+	_, ghosted, staleErr := storage.RunStaleCheck(ctx, now)
+	if staleErr != nil {
+		slog.ErrorContext(ctx, "failed to check for stale applications", "error", staleErr)
+	}
+	metrics.ObserveSchedulerRun(start, fired, ghosted, errors.Join(remindersErr, staleErr))
+}
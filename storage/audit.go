@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"ApplicationTracker/models"
+)
+
+const (
+	auditFile = "audit.json"
+	// maxAuditEvents bounds the audit log; the oldest events are dropped first
+	maxAuditEvents = 10000
+)
+
+// auditMutex guards the audit file
+var auditMutex = &sync.Mutex{}
+
+// readAuditEvents loads the audit log, oldest first; callers must hold auditMutex
+func readAuditEvents(ctx context.Context) ([]models.AuditEvent, error) {
+	events := []models.AuditEvent{}
+	if err := readJSONFile(ctx, auditFile, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// AppendAuditEvent adds an event to the audit log
+func AppendAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	return AppendAuditEvents(ctx, []models.AuditEvent{*event})
+}
+
+// AppendAuditEvents adds several events to the audit log in a single write
+func AppendAuditEvents(ctx context.Context, batch []models.AuditEvent) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+
+	events, err := readAuditEvents(ctx)
+	if err != nil {
+		return err
+	}
+	events = append(events, batch...)
+	if len(events) > maxAuditEvents {
+		events = events[len(events)-maxAuditEvents:]
+	}
+	return writeJSONFile(ctx, auditFile, events)
+}
+
+// ListAuditEvents returns up to limit of a workspace's most recent audit events, newest first
+func ListAuditEvents(ctx context.Context, workspaceID string, limit int) ([]models.AuditEvent, error) {
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+
+	events, err := readAuditEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.AuditEvent{}
+	for i := len(events) - 1; i >= 0 && len(result) < limit; i-- {
+		if events[i].WorkspaceID == workspaceID {
+			result = append(result, events[i])
+		}
+	}
+	return result, nil
+}
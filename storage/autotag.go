@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"ApplicationTracker/models"
+)
+
+const autoTagRulesFile = "autotag_rules.json"
+
+// MaxAutoTagRules caps the number of auto-tag rules in a workspace
+const MaxAutoTagRules = 100
+
+var (
+	// ErrAutoTagRuleNotFound is returned when an auto-tag rule is not found
+	ErrAutoTagRuleNotFound = errors.New("auto-tag rule not found")
+
+	// ErrTooManyAutoTagRules is returned when a workspace already has MaxAutoTagRules rules
+	ErrTooManyAutoTagRules = errors.New("too many auto-tag rules")
+
+	// autoTagRulesMutex guards the auto-tag rules file
+	autoTagRulesMutex = &sync.RWMutex{}
+)
+
+// readAutoTagRules loads all auto-tag rules; callers must hold autoTagRulesMutex
+func readAutoTagRules(ctx context.Context) ([]models.AutoTagRule, error) {
+	rules := []models.AutoTagRule{}
+	if err := readJSONFile(ctx, autoTagRulesFile, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ListAutoTagRules returns the auto-tag rules of a workspace, oldest first
+func ListAutoTagRules(ctx context.Context, workspaceID string) ([]models.AutoTagRule, error) {
+	autoTagRulesMutex.RLock()
+	defer autoTagRulesMutex.RUnlock()
+
+	rules, err := readAutoTagRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.AutoTagRule{}
+	for _, rule := range rules {
+		if rule.WorkspaceID == workspaceID {
+			result = append(result, rule)
+		}
+	}
+	return result, nil
+}
+
+// CreateAutoTagRule stores a new auto-tag rule
+func CreateAutoTagRule(ctx context.Context, rule *models.AutoTagRule) error {
+	autoTagRulesMutex.Lock()
+	defer autoTagRulesMutex.Unlock()
+
+	rules, err := readAutoTagRules(ctx)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, existing := range rules {
+		if existing.WorkspaceID == rule.WorkspaceID {
+			count++
+		}
+	}
+	if count >= MaxAutoTagRules {
+		return ErrTooManyAutoTagRules
+	}
+
+	slog.DebugContext(ctx, "creating auto-tag rule", "ruleId", rule.ID, "type", rule.Type)
+	return writeJSONFile(ctx, autoTagRulesFile, append(rules, *rule))
+}
+
+// DeleteAutoTagRule removes an auto-tag rule from a workspace
+func DeleteAutoTagRule(ctx context.Context, workspaceID, id string) error {
+	autoTagRulesMutex.Lock()
+	defer autoTagRulesMutex.Unlock()
+
+	rules, err := readAutoTagRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := slices.DeleteFunc(rules, func(rule models.AutoTagRule) bool {
+		return rule.WorkspaceID == workspaceID && rule.ID == id
+	})
+	if len(remaining) == len(rules) {
+		return ErrAutoTagRuleNotFound
+	}
+	return writeJSONFile(ctx, autoTagRulesFile, remaining)
+}
+
+// ApplyAutoTagRules runs a workspace's auto-tag rules over its applications and saves
+// the tags they add in a single write, never giving an application more than maxTags
+// tags. With dryRun set nothing is saved, which previews the changes.
+func ApplyAutoTagRules(ctx context.Context, workspaceID string, maxTags int, dryRun bool) ([]models.AutoTagChange, error) {
+	rules, err := ListAutoTagRules(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []models.AutoTagChange{}
+	for i := range applications {
+		app := &applications[i]
+		if app.OwnerID != workspaceID {
+			continue
+		}
+		if added := app.ApplyAutoTags(rules, maxTags); len(added) > 0 {
+			changes = append(changes, models.AutoTagChange{
+				ApplicationID: app.ID,
+				Company:       app.Company,
+				Position:      app.Position,
+				Tags:          added,
+			})
+		}
+	}
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	slog.DebugContext(ctx, "applying auto-tag rules", "applications", len(changes))
+	if err := saveApplicationsToFile(ctx, applications); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
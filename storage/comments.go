@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"ApplicationTracker/models"
+)
+
+const commentsFile = "comments.json"
+
+// commentsMutex guards the comments file
+var commentsMutex = &sync.RWMutex{}
+
+// readComments loads all comments; callers must hold commentsMutex
+func readComments(ctx context.Context) ([]models.Comment, error) {
+	comments := []models.Comment{}
+	if err := readJSONFile(ctx, commentsFile, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// ListComments returns the comments on an application, oldest first
+func ListComments(ctx context.Context, workspaceID, applicationID string) ([]models.Comment, error) {
+	commentsMutex.RLock()
+	defer commentsMutex.RUnlock()
+
+	comments, err := readComments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Comment{}
+	for _, c := range comments {
+		if c.WorkspaceID == workspaceID && c.ApplicationID == applicationID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// CreateComment stores a new comment
+func CreateComment(ctx context.Context, comment *models.Comment) error {
+	commentsMutex.Lock()
+	defer commentsMutex.Unlock()
+
+	comments, err := readComments(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "creating comment", "commentId", comment.ID, "applicationId", comment.ApplicationID)
+	return writeJSONFile(ctx, commentsFile, append(comments, *comment))
+}
+
+// moveComments moves the comments on one application to another, for merged applications
+func moveComments(ctx context.Context, workspaceID, fromID, toID string) error {
+	commentsMutex.Lock()
+	defer commentsMutex.Unlock()
+
+	comments, err := readComments(ctx)
+	if err != nil {
+		return err
+	}
+
+	moved := 0
+	for i, c := range comments {
+		if c.WorkspaceID == workspaceID && c.ApplicationID == fromID {
+			comments[i].ApplicationID = toID
+			moved++
+		}
+	}
+	if moved == 0 {
+		return nil
+	}
+	return writeJSONFile(ctx, commentsFile, comments)
+}
+
+// deleteComments removes the comments on a deleted application
+func deleteComments(ctx context.Context, workspaceID, applicationID string) error {
+	commentsMutex.Lock()
+	defer commentsMutex.Unlock()
+
+	comments, err := readComments(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := []models.Comment{}
+	for _, c := range comments {
+		if c.WorkspaceID != workspaceID || c.ApplicationID != applicationID {
+			remaining = append(remaining, c)
+		}
+	}
+	if len(remaining) == len(comments) {
+		return nil
+	}
+	return writeJSONFile(ctx, commentsFile, remaining)
+}
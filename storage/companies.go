@@ -0,0 +1,347 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"ApplicationTracker/models"
+)
+
+const companiesFile = "companies.json"
+
+var (
+	// ErrCompanyNotFound is returned when a company does not exist
+	ErrCompanyNotFound = errors.New("company not found")
+
+	// ErrCompanyInUse is returned when deleting a company that applications refer to
+	ErrCompanyInUse = errors.New("company has applications")
+
+	// companiesMutex guards the companies file; code that also needs the applications
+	// file must take mutex first
+	companiesMutex = &sync.RWMutex{}
+)
+
+// CompanyNameError is returned when a company's name or alias already names another company
+type CompanyNameError struct {
+	Name  string
+	Other string
+}
+
+// Error describes the conflicting name
+func (e *CompanyNameError) Error() string {
+	return fmt.Sprintf("%q already names the company %s", e.Name, e.Other)
+}
+
+// readCompanies loads all companies; callers must hold companiesMutex
+func readCompanies(ctx context.Context) ([]models.Company, error) {
+	companies := []models.Company{}
+	if err := readJSONFile(ctx, companiesFile, &companies); err != nil {
+		return nil, err
+	}
+	return companies, nil
+}
+
+// findCompany returns the index of the company in a workspace that name resolves to, or -1
+func findCompany(companies []models.Company, workspaceID, name string) int {
+	for i, c := range companies {
+		if c.WorkspaceID == workspaceID && c.HasName(name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkCompanyNames returns a CompanyNameError if the name or an alias of company
+// already names another company in its workspace
+func checkCompanyNames(companies []models.Company, company *models.Company) error {
+	for _, name := range append([]string{company.Name}, company.Aliases...) {
+		for _, other := range companies {
+			if other.WorkspaceID == company.WorkspaceID && other.ID != company.ID && other.HasName(name) {
+				return &CompanyNameError{Name: name, Other: other.Name}
+			}
+		}
+	}
+	return nil
+}
+
+// ListCompanies returns the companies in a workspace with their application history,
+// sorted by name
+func ListCompanies(ctx context.Context, workspaceID string) ([]models.CompanySummary, error) {
+	applications, err := GetAllApplications(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	byCompany := make(map[string][]models.Application)
+	for _, app := range applications {
+		byCompany[app.CompanyID] = append(byCompany[app.CompanyID], app)
+	}
+
+	companiesMutex.RLock()
+	companies, err := readCompanies(ctx)
+	companiesMutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.CompanySummary{}
+	for _, c := range companies {
+		if c.WorkspaceID == workspaceID {
+			result = append(result, models.CompanySummary{Company: c, History: models.NewCompanyHistory(byCompany[c.ID])})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result, nil
+}
+
+// GetCompany returns a company in a workspace by ID
+func GetCompany(ctx context.Context, workspaceID, id string) (*models.Company, error) {
+	companiesMutex.RLock()
+	defer companiesMutex.RUnlock()
+
+	companies, err := readCompanies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range companies {
+		if c.ID == id && c.WorkspaceID == workspaceID {
+			return &c, nil
+		}
+	}
+	return nil, ErrCompanyNotFound
+}
+
+// CompanyApplications returns the applications to a company, newest first
+func CompanyApplications(ctx context.Context, company *models.Company) ([]models.Application, error) {
+	applications, err := GetAllApplications(ctx, company.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Application{}
+	for _, app := range applications {
+		if app.CompanyID == company.ID {
+			result = append(result, app)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// ResolveCompany returns the company in a workspace that name resolves to, creating it
+// if there is none. It returns nil for names without letters or digits.
+func ResolveCompany(ctx context.Context, workspaceID, name string) (*models.Company, error) {
+	if models.NormalizeCompany(name) == "" {
+		return nil, nil
+	}
+
+	companiesMutex.Lock()
+	defer companiesMutex.Unlock()
+
+	companies, err := readCompanies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if i := findCompany(companies, workspaceID, name); i >= 0 {
+		return &companies[i], nil
+	}
+
+	company, err := models.NewCompany(workspaceID, models.CompanyDetails{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "creating company", "companyId", company.ID)
+	return company, writeJSONFile(ctx, companiesFile, append(companies, *company))
+}
+
+// CreateCompany stores a new company, failing with a CompanyNameError if its name or an
+// alias already names another company
+func CreateCompany(ctx context.Context, company *models.Company) error {
+	companiesMutex.Lock()
+	defer companiesMutex.Unlock()
+
+	companies, err := readCompanies(ctx)
+	if err != nil {
+		return err
+	}
+	if err := checkCompanyNames(companies, company); err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "creating company", "companyId", company.ID)
+	return writeJSONFile(ctx, companiesFile, append(companies, *company))
+}
+
+// SaveCompany replaces an existing company, failing with a CompanyNameError if its name
+// or an alias already names another company
+func SaveCompany(ctx context.Context, company *models.Company) error {
+	companiesMutex.Lock()
+	defer companiesMutex.Unlock()
+
+	companies, err := readCompanies(ctx)
+	if err != nil {
+		return err
+	}
+	if err := checkCompanyNames(companies, company); err != nil {
+		return err
+	}
+	for i, c := range companies {
+		if c.ID == company.ID && c.WorkspaceID == company.WorkspaceID {
+			companies[i] = *company
+			slog.DebugContext(ctx, "updating company", "companyId", company.ID)
+			return writeJSONFile(ctx, companiesFile, companies)
+		}
+	}
+	return ErrCompanyNotFound
+}
+
+// DeleteCompany removes a company that no application refers to
+func DeleteCompany(ctx context.Context, workspaceID, id string) (*models.Company, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	companiesMutex.Lock()
+	defer companiesMutex.Unlock()
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range applications {
+		if app.OwnerID == workspaceID && app.CompanyID == id {
+			return nil, ErrCompanyInUse
+		}
+	}
+
+	companies, err := readCompanies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var deleted *models.Company
+	remaining := []models.Company{}
+	for _, c := range companies {
+		if c.ID == id && c.WorkspaceID == workspaceID {
+			deleted = &c
+			continue
+		}
+		remaining = append(remaining, c)
+	}
+	if deleted == nil {
+		return nil, ErrCompanyNotFound
+	}
+
+	slog.DebugContext(ctx, "deleting company", "companyId", id)
+	return deleted, writeJSONFile(ctx, companiesFile, remaining)
+}
+
+// MergeCompanies folds the company mergeID into keepID: its applications move over, its
+// name and aliases become aliases of keepID and it is deleted. It returns the kept
+// company and the number of applications moved.
+func MergeCompanies(ctx context.Context, workspaceID, keepID, mergeID string) (*models.Company, int, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	companiesMutex.Lock()
+	defer companiesMutex.Unlock()
+
+	companies, err := readCompanies(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	keep, merged := -1, -1
+	for i, c := range companies {
+		if c.WorkspaceID != workspaceID {
+			continue
+		}
+		switch c.ID {
+		case keepID:
+			keep = i
+		case mergeID:
+			merged = i
+		}
+	}
+	if keep < 0 || merged < 0 {
+		return nil, 0, ErrCompanyNotFound
+	}
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	moved := 0
+	for i, app := range applications {
+		if app.OwnerID == workspaceID && app.CompanyID == mergeID {
+			applications[i].CompanyID = keepID
+			moved++
+		}
+	}
+
+	companies[keep].Absorb(companies[merged])
+	kept := companies[keep]
+	remaining := append(companies[:merged:merged], companies[merged+1:]...)
+
+	slog.DebugContext(ctx, "merging companies", "keepId", keepID, "mergeId", mergeID, "applications", moved)
+	if moved > 0 {
+		if err := saveApplicationsToFile(ctx, applications); err != nil {
+			return nil, 0, err
+		}
+	}
+	return &kept, moved, writeJSONFile(ctx, companiesFile, remaining)
+}
+
+// assignCompanies links applications that don't refer to a company yet, such as those
+// created before companies existed, to the company their name resolves to
+func assignCompanies(ctx context.Context) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	companiesMutex.Lock()
+	defer companiesMutex.Unlock()
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return err
+	}
+	companies, err := readCompanies(ctx)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(companies))
+	for _, c := range companies {
+		known[c.ID] = true
+	}
+
+	assigned, created := 0, 0
+	for i, app := range applications {
+		if app.OwnerID == "" || known[app.CompanyID] || models.NormalizeCompany(app.Company) == "" {
+			continue
+		}
+		j := findCompany(companies, app.OwnerID, app.Company)
+		if j < 0 {
+			company, err := models.NewCompany(app.OwnerID, models.CompanyDetails{Name: app.Company})
+			if err != nil {
+				continue
+			}
+			companies = append(companies, *company)
+			known[company.ID] = true
+			j = len(companies) - 1
+			created++
+		}
+		applications[i].CompanyID = companies[j].ID
+		assigned++
+	}
+	if assigned == 0 {
+		return nil
+	}
+
+	slog.InfoContext(ctx, "assigned applications to companies", "applications", assigned, "companiesCreated", created)
+	if err := writeJSONFile(ctx, companiesFile, companies); err != nil {
+		return err
+	}
+	return saveApplicationsToFile(ctx, applications)
+}
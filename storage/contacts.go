@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"ApplicationTracker/models"
+)
+
+const contactsFile = "contacts.json"
+
+var (
+	// ErrContactNotFound is returned when a contact does not exist
+	ErrContactNotFound = errors.New("contact not found")
+
+	// ErrContactNotLinked is returned when unlinking a contact from an application it isn't linked to
+	ErrContactNotLinked = errors.New("contact is not linked to this application")
+
+	// contactsMutex guards the contacts file
+	contactsMutex = &sync.RWMutex{}
+)
+
+// readContacts loads all contacts; callers must hold contactsMutex
+func readContacts(ctx context.Context) ([]models.Contact, error) {
+	contacts := []models.Contact{}
+	if err := readJSONFile(ctx, contactsFile, &contacts); err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+// ListContacts returns the contacts in a workspace matching query, sorted by name
+func ListContacts(ctx context.Context, workspaceID, query string) ([]models.Contact, error) {
+	contactsMutex.RLock()
+	defer contactsMutex.RUnlock()
+
+	contacts, err := readContacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Contact{}
+	for _, c := range contacts {
+		if c.WorkspaceID == workspaceID && c.Matches(query) {
+			result = append(result, c)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result, nil
+}
+
+// ListApplicationContacts returns the contacts linked to an application, sorted by name
+func ListApplicationContacts(ctx context.Context, workspaceID, applicationID string) ([]models.Contact, error) {
+	contacts, err := ListContacts(ctx, workspaceID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	linked := []models.Contact{}
+	for _, c := range contacts {
+		if c.IsLinked(applicationID) {
+			linked = append(linked, c)
+		}
+	}
+	return linked, nil
+}
+
+// ContactApplications returns the applications a contact is linked to
+func ContactApplications(ctx context.Context, contact *models.Contact) ([]models.Application, error) {
+	applications, err := GetAllApplications(ctx, contact.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := []models.Application{}
+	for _, app := range applications {
+		if contact.IsLinked(app.ID) {
+			linked = append(linked, app)
+		}
+	}
+	return linked, nil
+}
+
+// GetContact returns a contact in a workspace by ID
+func GetContact(ctx context.Context, workspaceID, id string) (*models.Contact, error) {
+	contactsMutex.RLock()
+	defer contactsMutex.RUnlock()
+
+	contacts, err := readContacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range contacts {
+		if c.ID == id && c.WorkspaceID == workspaceID {
+			return &c, nil
+		}
+	}
+	return nil, ErrContactNotFound
+}
+
+// CreateContact stores a new contact
+func CreateContact(ctx context.Context, contact *models.Contact) error {
+	contactsMutex.Lock()
+	defer contactsMutex.Unlock()
+
+	contacts, err := readContacts(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "creating contact", "contactId", contact.ID)
+	return writeJSONFile(ctx, contactsFile, append(contacts, *contact))
+}
+
+// SaveContactDetails stores the details of an updated contact, keeping the links
+// stored with it in case they changed since it was loaded
+func SaveContactDetails(ctx context.Context, contact *models.Contact) (*models.Contact, error) {
+	return modifyContact(ctx, contact.WorkspaceID, contact.ID, func(c *models.Contact) error {
+		c.ContactDetails = contact.ContactDetails
+		c.UpdatedAt = contact.UpdatedAt
+		return nil
+	})
+}
+
+// LinkContact links a contact to an application; linking it again is not an error
+func LinkContact(ctx context.Context, workspaceID, id, applicationID string) (*models.Contact, error) {
+	return modifyContact(ctx, workspaceID, id, func(c *models.Contact) error {
+		c.Link(applicationID)
+		return nil
+	})
+}
+
+// UnlinkContact removes the link between a contact and an application
+func UnlinkContact(ctx context.Context, workspaceID, id, applicationID string) (*models.Contact, error) {
+	return modifyContact(ctx, workspaceID, id, func(c *models.Contact) error {
+		if !c.Unlink(applicationID) {
+			return ErrContactNotLinked
+		}
+		return nil
+	})
+}
+
+// modifyContact applies change to a contact and saves it unless change fails
+func modifyContact(ctx context.Context, workspaceID, id string, change func(*models.Contact) error) (*models.Contact, error) {
+	contactsMutex.Lock()
+	defer contactsMutex.Unlock()
+
+	contacts, err := readContacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range contacts {
+		if contacts[i].ID != id || contacts[i].WorkspaceID != workspaceID {
+			continue
+		}
+		if err := change(&contacts[i]); err != nil {
+			return nil, err
+		}
+		slog.DebugContext(ctx, "updating contact", "contactId", id)
+		if err := writeJSONFile(ctx, contactsFile, contacts); err != nil {
+			return nil, err
+		}
+		return &contacts[i], nil
+	}
+	return nil, ErrContactNotFound
+}
+
+// DeleteContact removes a contact and returns it
+func DeleteContact(ctx context.Context, workspaceID, id string) (*models.Contact, error) {
+	contactsMutex.Lock()
+	defer contactsMutex.Unlock()
+
+	contacts, err := readContacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted *models.Contact
+	remaining := []models.Contact{}
+	for _, c := range contacts {
+		if c.ID == id && c.WorkspaceID == workspaceID {
+			deleted = &c
+			continue
+		}
+		remaining = append(remaining, c)
+	}
+	if deleted == nil {
+		return nil, ErrContactNotFound
+	}
+
+	slog.DebugContext(ctx, "deleting contact", "contactId", id)
+	return deleted, writeJSONFile(ctx, contactsFile, remaining)
+}
+
+// relinkContacts moves the links of contacts from one application to another: the
+// application is deleted when toID is empty and merged into toID otherwise
+func relinkContacts(ctx context.Context, workspaceID, fromID, toID string) error {
+	contactsMutex.Lock()
+	defer contactsMutex.Unlock()
+
+	contacts, err := readContacts(ctx)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for i := range contacts {
+		if contacts[i].WorkspaceID != workspaceID || !contacts[i].Unlink(fromID) {
+			continue
+		}
+		if toID != "" {
+			contacts[i].Link(toID)
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return writeJSONFile(ctx, contactsFile, contacts)
+}
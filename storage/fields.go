@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"ApplicationTracker/models"
+)
+
+const fieldDefinitionsFile = "custom_fields.json"
+
+// MaxFieldDefinitions caps the number of custom fields in a workspace
+const MaxFieldDefinitions = 50
+
+var (
+	// ErrFieldDefinitionNotFound is returned when a custom field is not found
+	ErrFieldDefinitionNotFound = errors.New("custom field not found")
+
+	// ErrFieldKeyTaken is returned when a workspace already has a custom field with the
+	// same key
+	ErrFieldKeyTaken = errors.New("a custom field with this name already exists")
+
+	// ErrTooManyFieldDefinitions is returned when a workspace already has
+	// MaxFieldDefinitions custom fields
+	ErrTooManyFieldDefinitions = errors.New("too many custom fields")
+
+	// fieldDefinitionsMutex guards the custom fields file
+	fieldDefinitionsMutex = &sync.RWMutex{}
+)
+
+// readFieldDefinitions loads all custom field definitions; callers must hold
+// fieldDefinitionsMutex
+func readFieldDefinitions(ctx context.Context) ([]models.FieldDefinition, error) {
+	definitions := []models.FieldDefinition{}
+	if err := readJSONFile(ctx, fieldDefinitionsFile, &definitions); err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// ListFieldDefinitions returns the custom fields of a workspace, oldest first
+func ListFieldDefinitions(ctx context.Context, workspaceID string) ([]models.FieldDefinition, error) {
+	fieldDefinitionsMutex.RLock()
+	defer fieldDefinitionsMutex.RUnlock()
+
+	definitions, err := readFieldDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.FieldDefinition{}
+	for _, definition := range definitions {
+		if definition.WorkspaceID == workspaceID {
+			result = append(result, definition)
+		}
+	}
+	return result, nil
+}
+
+// CreateFieldDefinition stores a new custom field; keys are unique within a workspace
+func CreateFieldDefinition(ctx context.Context, definition *models.FieldDefinition) error {
+	fieldDefinitionsMutex.Lock()
+	defer fieldDefinitionsMutex.Unlock()
+
+	definitions, err := readFieldDefinitions(ctx)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, existing := range definitions {
+		if existing.WorkspaceID != definition.WorkspaceID {
+			continue
+		}
+		if existing.Key == definition.Key {
+			return ErrFieldKeyTaken
+		}
+		count++
+	}
+	if count >= MaxFieldDefinitions {
+		return ErrTooManyFieldDefinitions
+	}
+
+	slog.DebugContext(ctx, "creating custom field", "fieldId", definition.ID, "type", definition.Type)
+	return writeJSONFile(ctx, fieldDefinitionsFile, append(definitions, *definition))
+}
+
+// DeleteFieldDefinition removes a custom field from a workspace along with its values
+// on the workspace's applications
+func DeleteFieldDefinition(ctx context.Context, workspaceID, id string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	fieldDefinitionsMutex.Lock()
+	defer fieldDefinitionsMutex.Unlock()
+
+	definitions, err := readFieldDefinitions(ctx)
+	if err != nil {
+		return err
+	}
+	var key string
+	remaining := slices.DeleteFunc(definitions, func(definition models.FieldDefinition) bool {
+		if definition.WorkspaceID == workspaceID && definition.ID == id {
+			key = definition.Key
+			return true
+		}
+		return false
+	})
+	if key == "" {
+		return ErrFieldDefinitionNotFound
+	}
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return err
+	}
+	cleared := 0
+	for i := range applications {
+		app := &applications[i]
+		if app.OwnerID != workspaceID {
+			continue
+		}
+		if _, ok := app.Fields[key]; ok {
+			delete(app.Fields, key)
+			if len(app.Fields) == 0 {
+				app.Fields = nil
+			}
+			cleared++
+		}
+	}
+	if cleared > 0 {
+		slog.DebugContext(ctx, "clearing deleted custom field", "key", key, "applications", cleared)
+		if err := saveApplicationsToFile(ctx, applications); err != nil {
+			return err
+		}
+	}
+	return writeJSONFile(ctx, fieldDefinitionsFile, remaining)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"ApplicationTracker/metrics"
+)
+
+// readJSONFile loads a JSON data file into v; a missing file leaves v untouched
+func readJSONFile(ctx context.Context, name string, v interface{}) error {
+	filePath := filepath.Join(dataDir, name)
+	start := time.Now()
+	data, err := os.ReadFile(filePath)
+	metrics.ObserveStorage("read", start)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read data file", "file", filePath, "error", err)
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	metrics.SetStorageFileSize(name, len(data))
+	if err := json.Unmarshal(data, v); err != nil {
+		slog.ErrorContext(ctx, "failed to unmarshal data file", "file", filePath, "bytes", len(data), "error", err)
+		return fmt.Errorf("failed to unmarshal %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeJSONFile replaces a JSON data file with v, writing to a temporary file first
+// so a failed write never leaves a truncated file behind
+func writeJSONFile(ctx context.Context, name string, v interface{}) error {
+	filePath := filepath.Join(dataDir, name)
+
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to marshal data file", "file", filePath, "error", err)
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	start := time.Now()
+	err = writeFileAtomic(filePath, jsonData, 0600)
+	metrics.ObserveStorage("write", start)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to write data file", "file", filePath, "error", err)
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	metrics.SetStorageFileSize(name, len(jsonData))
+	slog.DebugContext(ctx, "saved data file", "file", filePath, "bytes", len(jsonData))
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and renames it into place
+func writeFileAtomic(filePath string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return os.Rename(tmpName, filePath)
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+const idempotencyFile = "idempotency.json"
+
+var (
+	// ErrIdempotencyRecordNotFound is returned when no unexpired response is stored for an idempotency key
+	ErrIdempotencyRecordNotFound = errors.New("idempotency record not found")
+
+	// idempotencyMutex guards the idempotency file
+	idempotencyMutex = &sync.RWMutex{}
+)
+
+// readIdempotencyRecords loads all unexpired idempotency records; callers must hold idempotencyMutex
+func readIdempotencyRecords(ctx context.Context) ([]models.IdempotencyRecord, error) {
+	records := []models.IdempotencyRecord{}
+	if err := readJSONFile(ctx, idempotencyFile, &records); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	active := records[:0]
+	for _, record := range records {
+		if !record.Expired(now) {
+			active = append(active, record)
+		}
+	}
+	return active, nil
+}
+
+// GetIdempotencyRecord returns the unexpired response stored for a user's idempotency key
+func GetIdempotencyRecord(ctx context.Context, userID, key string) (*models.IdempotencyRecord, error) {
+	idempotencyMutex.RLock()
+	defer idempotencyMutex.RUnlock()
+
+	records, err := readIdempotencyRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.UserID == userID && record.Key == key {
+			return &record, nil
+		}
+	}
+	return nil, ErrIdempotencyRecordNotFound
+}
+
+// SaveIdempotencyRecord stores the response for an idempotency key, replacing any
+// earlier record for the key, and prunes expired records
+func SaveIdempotencyRecord(ctx context.Context, record *models.IdempotencyRecord) error {
+	idempotencyMutex.Lock()
+	defer idempotencyMutex.Unlock()
+
+	records, err := readIdempotencyRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	kept := records[:0]
+	for _, existing := range records {
+		if existing.UserID != record.UserID || existing.Key != record.Key {
+			kept = append(kept, existing)
+		}
+	}
+	return writeJSONFile(ctx, idempotencyFile, append(kept, *record))
+}
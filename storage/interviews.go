@@ -0,0 +1,243 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+const interviewsFile = "interviews.json"
+
+var (
+	// ErrInterviewNotFound is returned when an interview does not exist
+	ErrInterviewNotFound = errors.New("interview not found")
+
+	// interviewsMutex guards the interviews file
+	interviewsMutex = &sync.RWMutex{}
+)
+
+// readInterviews loads all interviews; callers must hold interviewsMutex
+func readInterviews(ctx context.Context) ([]models.Interview, error) {
+	interviews := []models.Interview{}
+	if err := readJSONFile(ctx, interviewsFile, &interviews); err != nil {
+		return nil, err
+	}
+	return interviews, nil
+}
+
+// sortInterviews orders interviews by start time
+func sortInterviews(interviews []models.Interview) {
+	sort.SliceStable(interviews, func(i, j int) bool {
+		return interviews[i].StartsAt.Before(interviews[j].StartsAt)
+	})
+}
+
+// ListInterviews returns the interviews of an application, earliest first
+func ListInterviews(ctx context.Context, workspaceID, applicationID string) ([]models.Interview, error) {
+	interviewsMutex.RLock()
+	defer interviewsMutex.RUnlock()
+
+	interviews, err := readInterviews(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Interview{}
+	for _, iv := range interviews {
+		if iv.WorkspaceID == workspaceID && iv.ApplicationID == applicationID {
+			result = append(result, iv)
+		}
+	}
+	sortInterviews(result)
+	return result, nil
+}
+
+// GetInterview returns an interview of an application by ID
+func GetInterview(ctx context.Context, workspaceID, applicationID, id string) (*models.Interview, error) {
+	interviewsMutex.RLock()
+	defer interviewsMutex.RUnlock()
+
+	interviews, err := readInterviews(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, iv := range interviews {
+		if iv.ID == id && iv.WorkspaceID == workspaceID && iv.ApplicationID == applicationID {
+			return &iv, nil
+		}
+	}
+	return nil, ErrInterviewNotFound
+}
+
+// CreateInterview stores a new interview, reporting whether it is the first interview of
+// its application
+func CreateInterview(ctx context.Context, interview *models.Interview) (bool, error) {
+	interviewsMutex.Lock()
+	defer interviewsMutex.Unlock()
+
+	interviews, err := readInterviews(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	first := true
+	for _, iv := range interviews {
+		if iv.WorkspaceID == interview.WorkspaceID && iv.ApplicationID == interview.ApplicationID {
+			first = false
+			break
+		}
+	}
+
+	slog.DebugContext(ctx, "creating interview", "interviewId", interview.ID, "applicationId", interview.ApplicationID)
+	return first, writeJSONFile(ctx, interviewsFile, append(interviews, *interview))
+}
+
+// ScheduleInterview stores a new interview for app. The first interview of an
+// application that is still "applied" moves it to "in_progress"; the returned status is
+// the one it had before, or empty if it didn't change.
+func ScheduleInterview(ctx context.Context, app *models.Application, interview *models.Interview) (string, error) {
+	first, err := CreateInterview(ctx, interview)
+	if err != nil {
+		return "", err
+	}
+
+	previousStatus := app.Status
+	if !first || !app.ProgressForInterview() {
+		return "", nil
+	}
+	if err := SaveApplication(ctx, app); err != nil {
+		return "", err
+	}
+	return previousStatus, nil
+}
+
+// SaveInterview replaces an existing interview
+func SaveInterview(ctx context.Context, interview *models.Interview) error {
+	interviewsMutex.Lock()
+	defer interviewsMutex.Unlock()
+
+	interviews, err := readInterviews(ctx)
+	if err != nil {
+		return err
+	}
+	for i, iv := range interviews {
+		if iv.ID == interview.ID && iv.WorkspaceID == interview.WorkspaceID && iv.ApplicationID == interview.ApplicationID {
+			interviews[i] = *interview
+			slog.DebugContext(ctx, "updating interview", "interviewId", interview.ID)
+			return writeJSONFile(ctx, interviewsFile, interviews)
+		}
+	}
+	return ErrInterviewNotFound
+}
+
+// DeleteInterview removes an interview from an application
+func DeleteInterview(ctx context.Context, workspaceID, applicationID, id string) error {
+	interviewsMutex.Lock()
+	defer interviewsMutex.Unlock()
+
+	interviews, err := readInterviews(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := []models.Interview{}
+	for _, iv := range interviews {
+		if !(iv.ID == id && iv.WorkspaceID == workspaceID && iv.ApplicationID == applicationID) {
+			remaining = append(remaining, iv)
+		}
+	}
+	if len(remaining) == len(interviews) {
+		return ErrInterviewNotFound
+	}
+
+	slog.DebugContext(ctx, "deleting interview", "interviewId", id, "applicationId", applicationID)
+	return writeJSONFile(ctx, interviewsFile, remaining)
+}
+
+// ListUpcomingInterviews returns the pending interviews in a workspace that haven't
+// ended by now and start before until, earliest first, with their applications
+func ListUpcomingInterviews(ctx context.Context, workspaceID string, now, until time.Time) ([]models.UpcomingInterview, error) {
+	applications, err := GetAllApplications(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.Application, len(applications))
+	for _, app := range applications {
+		byID[app.ID] = app
+	}
+
+	interviewsMutex.RLock()
+	interviews, err := readInterviews(ctx)
+	interviewsMutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var upcoming []models.Interview
+	for _, iv := range interviews {
+		if iv.WorkspaceID == workspaceID && iv.Upcoming(now) && iv.StartsAt.Before(until) {
+			upcoming = append(upcoming, iv)
+		}
+	}
+	sortInterviews(upcoming)
+
+	result := []models.UpcomingInterview{}
+	for _, iv := range upcoming {
+		app, ok := byID[iv.ApplicationID]
+		if !ok {
+			continue
+		}
+		result = append(result, models.UpcomingInterview{Interview: iv, Company: app.Company, Position: app.Position})
+	}
+	return result, nil
+}
+
+// moveInterviews moves the interviews of one application to another, for merged applications
+func moveInterviews(ctx context.Context, workspaceID, fromID, toID string) error {
+	interviewsMutex.Lock()
+	defer interviewsMutex.Unlock()
+
+	interviews, err := readInterviews(ctx)
+	if err != nil {
+		return err
+	}
+
+	moved := 0
+	for i, iv := range interviews {
+		if iv.WorkspaceID == workspaceID && iv.ApplicationID == fromID {
+			interviews[i].ApplicationID = toID
+			moved++
+		}
+	}
+	if moved == 0 {
+		return nil
+	}
+	return writeJSONFile(ctx, interviewsFile, interviews)
+}
+
+// deleteInterviews removes the interviews of a deleted application
+func deleteInterviews(ctx context.Context, workspaceID, applicationID string) error {
+	interviewsMutex.Lock()
+	defer interviewsMutex.Unlock()
+
+	interviews, err := readInterviews(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := []models.Interview{}
+	for _, iv := range interviews {
+		if iv.WorkspaceID != workspaceID || iv.ApplicationID != applicationID {
+			remaining = append(remaining, iv)
+		}
+	}
+	if len(remaining) == len(interviews) {
+		return nil
+	}
+	return writeJSONFile(ctx, interviewsFile, remaining)
+}
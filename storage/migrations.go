@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+const migrationsFile = "migrations.json"
+
+// Names of the one-time data migrations recorded in the migrations file
+const (
+	migrationWorkModeTags = "work-mode-tags"
+)
+
+// migrationsMutex guards the migrations file
+var migrationsMutex = &sync.Mutex{}
+
+// readMigrations loads the names of the migrations that already ran; callers must hold
+// migrationsMutex
+func readMigrations(ctx context.Context) ([]string, error) {
+	done := []string{}
+	if err := readJSONFile(ctx, migrationsFile, &done); err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+// migrationDone reports whether the named migration already ran
+func migrationDone(ctx context.Context, name string) (bool, error) {
+	migrationsMutex.Lock()
+	defer migrationsMutex.Unlock()
+
+	done, err := readMigrations(ctx)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(done, name), nil
+}
+
+// markMigrationDone records that the named migration ran so it never runs again
+func markMigrationDone(ctx context.Context, name string) error {
+	migrationsMutex.Lock()
+	defer migrationsMutex.Unlock()
+
+	done, err := readMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(done, name) {
+		return nil
+	}
+	return writeJSONFile(ctx, migrationsFile, append(done, name))
+}
+
+// runMigration runs a one-time data migration unless the migrations file records it
+// already ran, and records it once it succeeds. Migrations take their own locks, so
+// migrationsMutex is not held while they run; they only run from Initialize.
+func runMigration(ctx context.Context, name string, migrate func(context.Context) error) error {
+	done, err := migrationDone(ctx, name)
+	if err != nil || done {
+		return err
+	}
+	if err := migrate(ctx); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "ran data migration", "migration", name)
+	return markMigrationDone(ctx, name)
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"ApplicationTracker/models"
+)
+
+const notesFile = "notes.json"
+
+var (
+	// ErrNoteNotFound is returned when a note does not exist
+	ErrNoteNotFound = errors.New("note not found")
+
+	// notesMutex guards the notes file
+	notesMutex = &sync.RWMutex{}
+)
+
+// readNotes loads all notes; callers must hold notesMutex
+func readNotes(ctx context.Context) ([]models.Note, error) {
+	notes := []models.Note{}
+	if err := readJSONFile(ctx, notesFile, &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// ListNotes returns the notes on an application, newest first
+func ListNotes(ctx context.Context, workspaceID, applicationID string) ([]models.Note, error) {
+	notesMutex.RLock()
+	defer notesMutex.RUnlock()
+
+	notes, err := readNotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Note{}
+	for i := len(notes) - 1; i >= 0; i-- {
+		if notes[i].WorkspaceID == workspaceID && notes[i].ApplicationID == applicationID {
+			result = append(result, notes[i])
+		}
+	}
+	return result, nil
+}
+
+// GetNote returns a note on an application by ID
+func GetNote(ctx context.Context, workspaceID, applicationID, id string) (*models.Note, error) {
+	notesMutex.RLock()
+	defer notesMutex.RUnlock()
+
+	notes, err := readNotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notes {
+		if n.ID == id && n.WorkspaceID == workspaceID && n.ApplicationID == applicationID {
+			return &n, nil
+		}
+	}
+	return nil, ErrNoteNotFound
+}
+
+// CreateNote stores a new note
+func CreateNote(ctx context.Context, note *models.Note) error {
+	notesMutex.Lock()
+	defer notesMutex.Unlock()
+
+	notes, err := readNotes(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "creating note", "noteId", note.ID, "applicationId", note.ApplicationID)
+	return writeJSONFile(ctx, notesFile, append(notes, *note))
+}
+
+// UpdateNote replaces the body of a note on an application
+func UpdateNote(ctx context.Context, workspaceID, applicationID, id, body string) (*models.Note, error) {
+	notesMutex.Lock()
+	defer notesMutex.Unlock()
+
+	notes, err := readNotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, n := range notes {
+		if n.ID == id && n.WorkspaceID == workspaceID && n.ApplicationID == applicationID {
+			notes[i].Edit(body)
+			slog.DebugContext(ctx, "updating note", "noteId", id, "applicationId", applicationID)
+			if err := writeJSONFile(ctx, notesFile, notes); err != nil {
+				return nil, err
+			}
+			return &notes[i], nil
+		}
+	}
+	return nil, ErrNoteNotFound
+}
+
+// DeleteNote removes a note from an application
+func DeleteNote(ctx context.Context, workspaceID, applicationID, id string) error {
+	notesMutex.Lock()
+	defer notesMutex.Unlock()
+
+	notes, err := readNotes(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := []models.Note{}
+	for _, n := range notes {
+		if !(n.ID == id && n.WorkspaceID == workspaceID && n.ApplicationID == applicationID) {
+			remaining = append(remaining, n)
+		}
+	}
+	if len(remaining) == len(notes) {
+		return ErrNoteNotFound
+	}
+
+	slog.DebugContext(ctx, "deleting note", "noteId", id, "applicationId", applicationID)
+	return writeJSONFile(ctx, notesFile, remaining)
+}
+
+// noteText returns the lowercased text of the notes in a workspace, keyed by application ID, for search
+func noteText(ctx context.Context, workspaceID string) (map[string]string, error) {
+	notesMutex.RLock()
+	defer notesMutex.RUnlock()
+
+	notes, err := readNotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	text := make(map[string]string)
+	for _, n := range notes {
+		if n.WorkspaceID == workspaceID {
+			text[n.ApplicationID] += strings.ToLower(n.Body) + "\n"
+		}
+	}
+	return text, nil
+}
+
+// moveNotes moves the notes on one application to another, for merged applications
+func moveNotes(ctx context.Context, workspaceID, fromID, toID string) error {
+	notesMutex.Lock()
+	defer notesMutex.Unlock()
+
+	notes, err := readNotes(ctx)
+	if err != nil {
+		return err
+	}
+
+	moved := 0
+	for i, n := range notes {
+		if n.WorkspaceID == workspaceID && n.ApplicationID == fromID {
+			notes[i].ApplicationID = toID
+			moved++
+		}
+	}
+	if moved == 0 {
+		return nil
+	}
+	return writeJSONFile(ctx, notesFile, notes)
+}
+
+// deleteNotes removes the notes on a deleted application
+func deleteNotes(ctx context.Context, workspaceID, applicationID string) error {
+	notesMutex.Lock()
+	defer notesMutex.Unlock()
+
+	notes, err := readNotes(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := []models.Note{}
+	for _, n := range notes {
+		if n.WorkspaceID != workspaceID || n.ApplicationID != applicationID {
+			remaining = append(remaining, n)
+		}
+	}
+	if len(remaining) == len(notes) {
+		return nil
+	}
+	return writeJSONFile(ctx, notesFile, remaining)
+}
@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+const notificationsFile = "notifications.json"
+
+// MaxNotifications is the number of notifications kept per workspace; older ones are dropped
+const MaxNotifications = 100
+
+var (
+	// ErrNotificationNotFound is returned when a notification does not exist
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	// notificationsMutex guards the notifications file; it is taken after remindersMutex
+	notificationsMutex = &sync.RWMutex{}
+)
+
+// readNotificationsFile loads all notifications; callers must hold notificationsMutex
+func readNotificationsFile(ctx context.Context) ([]models.Notification, error) {
+	notifications := []models.Notification{}
+	if err := readJSONFile(ctx, notificationsFile, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// ListNotifications returns the notifications of a workspace, newest first, and how many are unread
+func ListNotifications(ctx context.Context, workspaceID string) ([]models.Notification, int, error) {
+	notificationsMutex.RLock()
+	defer notificationsMutex.RUnlock()
+
+	notifications, err := readNotificationsFile(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := []models.Notification{}
+	unread := 0
+	for i := len(notifications) - 1; i >= 0; i-- {
+		if notifications[i].WorkspaceID == workspaceID {
+			result = append(result, notifications[i])
+			if notifications[i].ReadAt == nil {
+				unread++
+			}
+		}
+	}
+	return result, unread, nil
+}
+
+// GetNotification returns a notification of a workspace by ID
+func GetNotification(ctx context.Context, workspaceID, id string) (*models.Notification, error) {
+	notificationsMutex.RLock()
+	defer notificationsMutex.RUnlock()
+
+	notifications, err := readNotificationsFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notifications {
+		if n.ID == id && n.WorkspaceID == workspaceID {
+			return &n, nil
+		}
+	}
+	return nil, ErrNotificationNotFound
+}
+
+// MarkNotificationRead marks a notification of a workspace read
+func MarkNotificationRead(ctx context.Context, workspaceID, id string) error {
+	found := false
+	err := markNotificationsRead(ctx, func(n models.Notification) bool {
+		match := n.WorkspaceID == workspaceID && n.ID == id
+		found = found || match
+		return match
+	})
+	if err == nil && !found {
+		return ErrNotificationNotFound
+	}
+	return err
+}
+
+// MarkAllNotificationsRead marks every notification of a workspace read
+func MarkAllNotificationsRead(ctx context.Context, workspaceID string) error {
+	return markNotificationsRead(ctx, func(n models.Notification) bool {
+		return n.WorkspaceID == workspaceID
+	})
+}
+
+// readReminderNotifications marks the notifications fired by a reminder read
+func readReminderNotifications(ctx context.Context, workspaceID, reminderID string) error {
+	return markNotificationsRead(ctx, func(n models.Notification) bool {
+		return n.WorkspaceID == workspaceID && n.ReminderID == reminderID
+	})
+}
+
+// markNotificationsRead marks the unread notifications that match read
+func markNotificationsRead(ctx context.Context, match func(models.Notification) bool) error {
+	notificationsMutex.Lock()
+	defer notificationsMutex.Unlock()
+
+	notifications, err := readNotificationsFile(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	changed := 0
+	for i, n := range notifications {
+		if match(n) && n.ReadAt == nil {
+			notifications[i].MarkRead(now)
+			changed++
+		}
+	}
+	if changed == 0 {
+		return nil
+	}
+	return writeJSONFile(ctx, notificationsFile, notifications)
+}
+
+// addNotifications stores new notifications, dropping the oldest of workspaces that
+// exceed MaxNotifications
+func addNotifications(ctx context.Context, added []models.Notification) error {
+	if len(added) == 0 {
+		return nil
+	}
+	notificationsMutex.Lock()
+	defer notificationsMutex.Unlock()
+
+	notifications, err := readNotificationsFile(ctx)
+	if err != nil {
+		return err
+	}
+	notifications = append(notifications, added...)
+
+	counts := make(map[string]int)
+	for _, n := range notifications {
+		counts[n.WorkspaceID]++
+	}
+	kept := make([]models.Notification, 0, len(notifications))
+	for _, n := range notifications {
+		if counts[n.WorkspaceID] > MaxNotifications {
+			counts[n.WorkspaceID]--
+			continue
+		}
+		kept = append(kept, n)
+	}
+
+	slog.DebugContext(ctx, "adding notifications", "count", len(added))
+	return writeJSONFile(ctx, notificationsFile, kept)
+}
+
+// moveNotifications moves the notifications of a merged application to the application
+// it was merged into
+func moveNotifications(ctx context.Context, workspaceID, fromID, toID string) error {
+	notificationsMutex.Lock()
+	defer notificationsMutex.Unlock()
+
+	notifications, err := readNotificationsFile(ctx)
+	if err != nil {
+		return err
+	}
+	moved := 0
+	for i, n := range notifications {
+		if n.WorkspaceID == workspaceID && n.ApplicationID == fromID {
+			notifications[i].ApplicationID = toID
+			moved++
+		}
+	}
+	if moved == 0 {
+		return nil
+	}
+	return writeJSONFile(ctx, notificationsFile, notifications)
+}
+
+// deleteNotifications removes the notifications that match remove
+func deleteNotifications(ctx context.Context, remove func(models.Notification) bool) error {
+	notificationsMutex.Lock()
+	defer notificationsMutex.Unlock()
+
+	notifications, err := readNotificationsFile(ctx)
+	if err != nil {
+		return err
+	}
+	remaining := []models.Notification{}
+	for _, n := range notifications {
+		if !remove(n) {
+			remaining = append(remaining, n)
+		}
+	}
+	if len(remaining) == len(notifications) {
+		return nil
+	}
+	return writeJSONFile(ctx, notificationsFile, remaining)
+}
@@ -0,0 +1,437 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+const (
+	remindersFile     = "reminders.json"
+	reminderRulesFile = "reminder_rules.json"
+)
+
+// MaxReminderRules caps the number of reminder rules in a workspace
+const MaxReminderRules = 20
+
+var (
+	// ErrReminderNotFound is returned when a reminder does not exist
+	ErrReminderNotFound = errors.New("reminder not found")
+
+	// ErrReminderRuleNotFound is returned when a reminder rule does not exist
+	ErrReminderRuleNotFound = errors.New("reminder rule not found")
+
+	// ErrTooManyReminderRules is returned when a workspace already has MaxReminderRules rules
+	ErrTooManyReminderRules = errors.New("too many reminder rules")
+
+	// remindersMutex guards the reminders file and reminderRulesMutex the rules file;
+	// code that needs both takes reminderRulesMutex first, and neither is held while
+	// taking mutex
+	remindersMutex     = &sync.RWMutex{}
+	reminderRulesMutex = &sync.RWMutex{}
+)
+
+// reminderRuleSet holds the reminder rules of a workspace. A workspace without a set
+// gets the default rules; once stored, its rules are whatever it kept, even none.
+type reminderRuleSet struct {
+	WorkspaceID string                `json:"workspaceId"`
+	Rules       []models.ReminderRule `json:"rules"`
+}
+
+// readReminders loads all reminders; callers must hold remindersMutex
+func readReminders(ctx context.Context) ([]models.Reminder, error) {
+	reminders := []models.Reminder{}
+	if err := readJSONFile(ctx, remindersFile, &reminders); err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// readReminderRuleSets loads the reminder rules of all workspaces; callers must hold
+// reminderRulesMutex
+func readReminderRuleSets(ctx context.Context) ([]reminderRuleSet, error) {
+	sets := []reminderRuleSet{}
+	if err := readJSONFile(ctx, reminderRulesFile, &sets); err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// ruleSetIndex returns the index of a workspace's rule set, adding the default rules to
+// sets for workspaces that don't have one yet. Nothing is saved: callers write sets back
+// only when they change the rules. Callers must hold reminderRulesMutex.
+func ruleSetIndex(ctx context.Context, sets *[]reminderRuleSet, workspaceID string) (int, error) {
+	for i, set := range *sets {
+		if set.WorkspaceID == workspaceID {
+			return i, nil
+		}
+	}
+	// The workspace ID is its owner's ID, and the default rules date from the account
+	var createdAt time.Time
+	owner, err := GetUserByID(ctx, workspaceID)
+	switch {
+	case err == nil:
+		createdAt = owner.CreatedAt
+	case !errors.Is(err, ErrUserNotFound):
+		return 0, err
+	}
+	*sets = append(*sets, reminderRuleSet{WorkspaceID: workspaceID, Rules: models.DefaultReminderRules(workspaceID, createdAt)})
+	return len(*sets) - 1, nil
+}
+
+// ListReminders returns the reminders on an application, soonest due first
+func ListReminders(ctx context.Context, workspaceID, applicationID string) ([]models.Reminder, error) {
+	remindersMutex.RLock()
+	defer remindersMutex.RUnlock()
+
+	reminders, err := readReminders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Reminder{}
+	for _, r := range reminders {
+		if r.WorkspaceID == workspaceID && r.ApplicationID == applicationID {
+			result = append(result, r)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DueAt.Before(result[j].DueAt)
+	})
+	return result, nil
+}
+
+// ListOpenReminders returns the reminders in a workspace that aren't done, soonest due first
+func ListOpenReminders(ctx context.Context, workspaceID string) ([]models.Reminder, error) {
+	remindersMutex.RLock()
+	defer remindersMutex.RUnlock()
+
+	reminders, err := readReminders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Reminder{}
+	for _, r := range reminders {
+		if r.WorkspaceID == workspaceID && !r.Done {
+			result = append(result, r)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DueAt.Before(result[j].DueAt)
+	})
+	return result, nil
+}
+
+// GetReminder returns a reminder on an application by ID
+func GetReminder(ctx context.Context, workspaceID, applicationID, id string) (*models.Reminder, error) {
+	remindersMutex.RLock()
+	defer remindersMutex.RUnlock()
+
+	reminders, err := readReminders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reminders {
+		if r.ID == id && r.WorkspaceID == workspaceID && r.ApplicationID == applicationID {
+			return &r, nil
+		}
+	}
+	return nil, ErrReminderNotFound
+}
+
+// CreateReminder stores a new reminder
+func CreateReminder(ctx context.Context, reminder *models.Reminder) error {
+	remindersMutex.Lock()
+	defer remindersMutex.Unlock()
+
+	reminders, err := readReminders(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "creating reminder", "reminderId", reminder.ID, "applicationId", reminder.ApplicationID)
+	return writeJSONFile(ctx, remindersFile, append(reminders, *reminder))
+}
+
+// SaveReminder replaces an existing reminder. Once a reminder is done or due again
+// later, the notifications it fired are marked read.
+func SaveReminder(ctx context.Context, reminder *models.Reminder) error {
+	remindersMutex.Lock()
+	defer remindersMutex.Unlock()
+
+	reminders, err := readReminders(ctx)
+	if err != nil {
+		return err
+	}
+	found := false
+	for i, r := range reminders {
+		if r.ID == reminder.ID && r.WorkspaceID == reminder.WorkspaceID && r.ApplicationID == reminder.ApplicationID {
+			reminders[i] = *reminder
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrReminderNotFound
+	}
+
+	slog.DebugContext(ctx, "updating reminder", "reminderId", reminder.ID, "state", reminder.State())
+	if err := writeJSONFile(ctx, remindersFile, reminders); err != nil {
+		return err
+	}
+	if reminder.State() != models.ReminderFired {
+		return readReminderNotifications(ctx, reminder.WorkspaceID, reminder.ID)
+	}
+	return nil
+}
+
+// DeleteReminder removes a reminder from an application along with its notifications
+func DeleteReminder(ctx context.Context, workspaceID, applicationID, id string) error {
+	remindersMutex.Lock()
+	defer remindersMutex.Unlock()
+
+	reminders, err := readReminders(ctx)
+	if err != nil {
+		return err
+	}
+	remaining := []models.Reminder{}
+	for _, r := range reminders {
+		if r.ID != id || r.WorkspaceID != workspaceID || r.ApplicationID != applicationID {
+			remaining = append(remaining, r)
+		}
+	}
+	if len(remaining) == len(reminders) {
+		return ErrReminderNotFound
+	}
+
+	slog.DebugContext(ctx, "deleting reminder", "reminderId", id)
+	if err := writeJSONFile(ctx, remindersFile, remaining); err != nil {
+		return err
+	}
+	return deleteNotifications(ctx, func(n models.Notification) bool {
+		return n.WorkspaceID == workspaceID && n.ReminderID == id
+	})
+}
+
+// moveReminders moves the reminders of a merged application to the application it was merged into
+func moveReminders(ctx context.Context, workspaceID, fromID, toID string) error {
+	remindersMutex.Lock()
+	defer remindersMutex.Unlock()
+
+	reminders, err := readReminders(ctx)
+	if err != nil {
+		return err
+	}
+
+	moved := 0
+	for i, r := range reminders {
+		if r.WorkspaceID == workspaceID && r.ApplicationID == fromID {
+			reminders[i].ApplicationID = toID
+			moved++
+		}
+	}
+	if moved == 0 {
+		return nil
+	}
+	if err := writeJSONFile(ctx, remindersFile, reminders); err != nil {
+		return err
+	}
+	return moveNotifications(ctx, workspaceID, fromID, toID)
+}
+
+// deleteReminders removes the reminders on a deleted application and their notifications
+func deleteReminders(ctx context.Context, workspaceID, applicationID string) error {
+	remindersMutex.Lock()
+	defer remindersMutex.Unlock()
+
+	reminders, err := readReminders(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := []models.Reminder{}
+	for _, r := range reminders {
+		if r.WorkspaceID != workspaceID || r.ApplicationID != applicationID {
+			remaining = append(remaining, r)
+		}
+	}
+	if len(remaining) != len(reminders) {
+		if err := writeJSONFile(ctx, remindersFile, remaining); err != nil {
+			return err
+		}
+	}
+	return deleteNotifications(ctx, func(n models.Notification) bool {
+		return n.WorkspaceID == workspaceID && n.ApplicationID == applicationID
+	})
+}
+
+// ListReminderRules returns the reminder rules of a workspace, oldest first
+func ListReminderRules(ctx context.Context, workspaceID string) ([]models.ReminderRule, error) {
+	reminderRulesMutex.RLock()
+	defer reminderRulesMutex.RUnlock()
+
+	sets, err := readReminderRuleSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i, err := ruleSetIndex(ctx, &sets, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return sets[i].Rules, nil
+}
+
+// GetReminderRule returns a reminder rule of a workspace by ID
+func GetReminderRule(ctx context.Context, workspaceID, id string) (*models.ReminderRule, error) {
+	rules, err := ListReminderRules(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.ID == id {
+			return &rule, nil
+		}
+	}
+	return nil, ErrReminderRuleNotFound
+}
+
+// CreateReminderRule stores a new reminder rule
+func CreateReminderRule(ctx context.Context, rule *models.ReminderRule) error {
+	reminderRulesMutex.Lock()
+	defer reminderRulesMutex.Unlock()
+
+	sets, err := readReminderRuleSets(ctx)
+	if err != nil {
+		return err
+	}
+	i, err := ruleSetIndex(ctx, &sets, rule.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if len(sets[i].Rules) >= MaxReminderRules {
+		return ErrTooManyReminderRules
+	}
+	sets[i].Rules = append(sets[i].Rules, *rule)
+
+	slog.DebugContext(ctx, "creating reminder rule", "ruleId", rule.ID)
+	return writeJSONFile(ctx, reminderRulesFile, sets)
+}
+
+// SaveReminderRule replaces an existing reminder rule
+func SaveReminderRule(ctx context.Context, rule *models.ReminderRule) error {
+	return modifyReminderRules(ctx, rule.WorkspaceID, rule.ID, func(rules []models.ReminderRule, i int) []models.ReminderRule {
+		rules[i] = *rule
+		return rules
+	})
+}
+
+// DeleteReminderRule removes a reminder rule; reminders it already created are kept
+func DeleteReminderRule(ctx context.Context, workspaceID, id string) error {
+	return modifyReminderRules(ctx, workspaceID, id, func(rules []models.ReminderRule, i int) []models.ReminderRule {
+		return append(rules[:i:i], rules[i+1:]...)
+	})
+}
+
+// modifyReminderRules applies change to the rules of a workspace at the index of rule id
+func modifyReminderRules(ctx context.Context, workspaceID, id string, change func([]models.ReminderRule, int) []models.ReminderRule) error {
+	reminderRulesMutex.Lock()
+	defer reminderRulesMutex.Unlock()
+
+	sets, err := readReminderRuleSets(ctx)
+	if err != nil {
+		return err
+	}
+	i, err := ruleSetIndex(ctx, &sets, workspaceID)
+	if err != nil {
+		return err
+	}
+	for j, rule := range sets[i].Rules {
+		if rule.ID == id {
+			sets[i].Rules = change(sets[i].Rules, j)
+			slog.DebugContext(ctx, "updating reminder rules", "ruleId", id)
+			return writeJSONFile(ctx, reminderRulesFile, sets)
+		}
+	}
+	return ErrReminderRuleNotFound
+}
+
+// RunReminders creates the reminders that reminder rules call for and fires every
+// reminder due at now into its workspace's notifications. It returns the number of
+// reminders fired.
+func RunReminders(ctx context.Context, now time.Time) (int, error) {
+	mutex.RLock()
+	applications, err := readApplicationsFile(ctx)
+	mutex.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+	byWorkspace := make(map[string][]models.Application)
+	subjects := make(map[string]string, len(applications))
+	for _, app := range applications {
+		if app.OwnerID == "" {
+			continue
+		}
+		byWorkspace[app.OwnerID] = append(byWorkspace[app.OwnerID], app)
+		subjects[app.ID] = app.Company + " - " + app.Position
+	}
+
+	reminderRulesMutex.RLock()
+	defer reminderRulesMutex.RUnlock()
+	sets, err := readReminderRuleSets(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rules := make(map[string][]models.ReminderRule, len(byWorkspace))
+	for workspaceID := range byWorkspace {
+		i, err := ruleSetIndex(ctx, &sets, workspaceID)
+		if err != nil {
+			return 0, err
+		}
+		rules[workspaceID] = sets[i].Rules
+	}
+
+	remindersMutex.Lock()
+	defer remindersMutex.Unlock()
+	reminders, err := readReminders(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for workspaceID, apps := range byWorkspace {
+		for _, rule := range rules[workspaceID] {
+			for _, app := range apps {
+				reminder := rule.ReminderFor(app, now)
+				if reminder == nil || slices.ContainsFunc(reminders, func(r models.Reminder) bool { return r.IsFor(rule, app) }) {
+					continue
+				}
+				reminders = append(reminders, *reminder)
+				created++
+			}
+		}
+	}
+
+	notifications := []models.Notification{}
+	for i, r := range reminders {
+		subject, ok := subjects[r.ApplicationID]
+		if ok && r.IsDue(now) {
+			notifications = append(notifications, reminders[i].Fire(subject, now))
+		}
+	}
+	if created == 0 && len(notifications) == 0 {
+		return 0, nil
+	}
+
+	slog.InfoContext(ctx, "ran reminders", "created", created, "fired", len(notifications))
+	if err := writeJSONFile(ctx, remindersFile, reminders); err != nil {
+		return 0, err
+	}
+	return len(notifications), addNotifications(ctx, notifications)
+}
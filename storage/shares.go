@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+const (
+	sharesFile = "shares.json"
+	secretFile = "secret.key"
+)
+
+var (
+	// ErrShareNotFound is returned when a sharing link is not found
+	ErrShareNotFound = errors.New("share not found")
+
+	// sharesMutex guards the shares file
+	sharesMutex = &sync.RWMutex{}
+
+	// secretMutex guards the lazily loaded signing secret
+	secretMutex = &sync.Mutex{}
+	secret      []byte
+)
+
+// SigningSecret returns the server's secret key for signing links, creating it on first use
+func SigningSecret() ([]byte, error) {
+	secretMutex.Lock()
+	defer secretMutex.Unlock()
+
+	if secret != nil {
+		return secret, nil
+	}
+
+	filePath := filepath.Join(dataDir, secretFile)
+	data, err := os.ReadFile(filePath)
+	if err == nil && len(data) >= 32 {
+		secret = data
+		return secret, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read signing secret: %w", err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate signing secret: %w", err)
+	}
+	if err := writeFileAtomic(filePath, key, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write signing secret: %w", err)
+	}
+	slog.Info("created signing secret", "file", filePath)
+	secret = key
+	return secret, nil
+}
+
+// readShares loads all sharing links; callers must hold sharesMutex
+func readShares(ctx context.Context) ([]models.Share, error) {
+	shares := []models.Share{}
+	if err := readJSONFile(ctx, sharesFile, &shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// ListShares returns the sharing links created by a user
+func ListShares(ctx context.Context, ownerID string) ([]models.Share, error) {
+	sharesMutex.RLock()
+	defer sharesMutex.RUnlock()
+
+	shares, err := readShares(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := []models.Share{}
+	for _, share := range shares {
+		if share.OwnerID == ownerID {
+			owned = append(owned, share)
+		}
+	}
+	return owned, nil
+}
+
+// GetShare returns a sharing link by ID
+func GetShare(ctx context.Context, id string) (*models.Share, error) {
+	sharesMutex.RLock()
+	defer sharesMutex.RUnlock()
+
+	shares, err := readShares(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, share := range shares {
+		if share.ID == id {
+			return &share, nil
+		}
+	}
+	return nil, ErrShareNotFound
+}
+
+// CreateShare stores a new sharing link
+func CreateShare(ctx context.Context, share *models.Share) error {
+	sharesMutex.Lock()
+	defer sharesMutex.Unlock()
+
+	shares, err := readShares(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "creating share", "shareId", share.ID, "userId", share.OwnerID)
+	return writeJSONFile(ctx, sharesFile, append(shares, *share))
+}
+
+// RevokeShare marks a user's sharing link as revoked
+func RevokeShare(ctx context.Context, ownerID, id string) error {
+	sharesMutex.Lock()
+	defer sharesMutex.Unlock()
+
+	shares, err := readShares(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range shares {
+		if shares[i].ID == id && shares[i].OwnerID == ownerID {
+			if shares[i].RevokedAt == nil {
+				now := time.Now()
+				shares[i].RevokedAt = &now
+			}
+			slog.InfoContext(ctx, "revoking share", "shareId", id, "userId", ownerID)
+			return writeJSONFile(ctx, sharesFile, shares)
+		}
+	}
+	return ErrShareNotFound
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+const staleSettingsFile = "stale_settings.json"
+
+// staleSettingsMutex guards the stale settings file; it is taken after mutex
+var staleSettingsMutex = &sync.RWMutex{}
+
+// readStaleSettings loads the stale settings of all workspaces that changed them;
+// callers must hold staleSettingsMutex
+func readStaleSettings(ctx context.Context) ([]models.StaleSettings, error) {
+	settings := []models.StaleSettings{}
+	if err := readJSONFile(ctx, staleSettingsFile, &settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// staleSettingsFor returns the settings of a workspace from all settings, or the defaults
+func staleSettingsFor(all []models.StaleSettings, workspaceID string) models.StaleSettings {
+	for _, s := range all {
+		if s.WorkspaceID == workspaceID {
+			return s
+		}
+	}
+	return models.DefaultStaleSettings(workspaceID)
+}
+
+// GetStaleSettings returns the stale settings of a workspace, or the defaults if it
+// hasn't changed them
+func GetStaleSettings(ctx context.Context, workspaceID string) (*models.StaleSettings, error) {
+	staleSettingsMutex.RLock()
+	defer staleSettingsMutex.RUnlock()
+
+	all, err := readStaleSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settings := staleSettingsFor(all, workspaceID)
+	return &settings, nil
+}
+
+// SaveStaleSettings replaces the stale settings of a workspace; applications are
+// flagged against them on the scheduler's next run
+func SaveStaleSettings(ctx context.Context, settings *models.StaleSettings) error {
+	staleSettingsMutex.Lock()
+	defer staleSettingsMutex.Unlock()
+
+	all, err := readStaleSettings(ctx)
+	if err != nil {
+		return err
+	}
+	found := false
+	for i, s := range all {
+		if s.WorkspaceID == settings.WorkspaceID {
+			all[i] = *settings
+			found = true
+			break
+		}
+	}
+	if !found {
+		all = append(all, *settings)
+	}
+
+	slog.DebugContext(ctx, "saving stale settings", "workspaceId", settings.WorkspaceID)
+	return writeJSONFile(ctx, staleSettingsFile, all)
+}
+
+// ListStaleApplications returns the applications of a workspace that are flagged
+// stale, least recently active first
+func ListStaleApplications(ctx context.Context, workspaceID string) ([]models.Application, error) {
+	applications, err := GetAllApplications(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Application{}
+	for _, app := range applications {
+		if app.IsStale() {
+			result = append(result, app)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ActivityAt().Before(result[j].ActivityAt())
+	})
+	return result, nil
+}
+
+// RunStaleCheck flags the applications that are stale at now under their workspace's
+// settings, clears the flag of those that aren't, and moves the ones due for it to
+// ghosted with a note explaining why. It returns the number of applications flagged
+// and ghosted.
+func RunStaleCheck(ctx context.Context, now time.Time) (int, int, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	staleSettingsMutex.RLock()
+	all, err := readStaleSettings(ctx)
+	staleSettingsMutex.RUnlock()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	changed := false
+	flagged := 0
+	notes := []*models.Note{}
+	for i := range applications {
+		app := &applications[i]
+		if app.OwnerID == "" {
+			continue
+		}
+		settings := staleSettingsFor(all, app.OwnerID)
+		switch {
+		case settings.ShouldGhost(*app, now):
+			notes = append(notes, models.NewSystemNote(app.OwnerID, app.ID, settings.GhostReason(*app)))
+			app.UpdateStatus(models.ApplicationStatus.Ghosted)
+			app.StaleAt = nil
+			changed = true
+		case settings.IsStale(*app, now):
+			if !app.IsStale() {
+				app.StaleAt = &now
+				flagged++
+				changed = true
+			}
+		case app.StaleAt != nil:
+			app.StaleAt = nil
+			changed = true
+		}
+	}
+	if !changed {
+		return 0, 0, nil
+	}
+
+	slog.InfoContext(ctx, "ran stale check", "flagged", flagged, "ghosted", len(notes))
+	if err := saveApplicationsToFile(ctx, applications); err != nil {
+		return 0, 0, err
+	}
+	for _, note := range notes {
+		if err := CreateNote(ctx, note); err != nil {
+			return flagged, len(notes), err
+		}
+	}
+	return flagged, len(notes), nil
+}
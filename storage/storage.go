@@ -1,15 +1,19 @@
 package storage
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"ApplicationTracker/metrics"
 	"ApplicationTracker/models"
 )
 
@@ -22,60 +26,106 @@ var (
 	// ErrNotFound is returned when an application is not found
 	ErrNotFound = errors.New("application not found")
 
+	// ErrNoOwner is returned when saving an application that has no owner
+	ErrNoOwner = errors.New("application has no owner")
+
+	// ErrTooManyTags is returned when a merge would leave an application with too many tags
+	ErrTooManyTags = errors.New("too many tags")
+
 	// mutex to prevent concurrent file access
 	mutex = &sync.RWMutex{}
 )
 
 // Initialize creates the data directory if it doesn't exist
 func Initialize() error {
-	log.Printf("Initializing storage...")
+	slog.Info("initializing storage", "dir", dataDir)
 
 	if _, err := os.Stat(dataDir); os.IsNotExist(err) {
-		log.Printf("Data directory does not exist, creating: %s", dataDir)
+		slog.Info("creating data directory", "dir", dataDir)
 		if err := os.MkdirAll(dataDir, 0755); err != nil {
-			log.Printf("ERROR: Failed to create data directory: %v", err)
 			return fmt.Errorf("failed to create data directory: %w", err)
 		}
-		log.Printf("Created data directory: %s", dataDir)
-	} else {
-		log.Printf("Data directory exists: %s", dataDir)
 	}
 
 	// Create applications file if it doesn't exist
 	filePath := filepath.Join(dataDir, applicationsFile)
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		log.Printf("Applications file does not exist, creating: %s", filePath)
+		slog.Info("creating applications file", "file", filePath)
 
 		// Create an empty applications array
 		emptyData := []models.Application{}
 		jsonData, err := json.MarshalIndent(emptyData, "", "  ")
 		if err != nil {
-			log.Printf("ERROR: Failed to marshal empty applications: %v", err)
 			return fmt.Errorf("failed to marshal empty applications: %w", err)
 		}
 
 		if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
-			log.Printf("ERROR: Failed to create applications file: %v", err)
 			return fmt.Errorf("failed to create applications file: %w", err)
 		}
+	} else if err := validateApplicationsFile(filePath); err != nil {
+		// We don't return an error here to allow the application to start,
+		// but we log a warning so the user knows there might be issues
+		slog.Warn("applications file contains invalid JSON, fix or recreate it to avoid runtime errors",
+			"file", filePath, "error", err)
+	}
 
-		log.Printf("Created applications file: %s", filePath)
-	} else {
-		log.Printf("Applications file exists: %s", filePath)
+	// Applications created before accounts existed belong to the first account
+	if err := claimForFirstUser(context.Background()); err != nil {
+		slog.Warn("failed to assign unowned applications", "error", err)
+	}
 
-		// Verify the file contains valid JSON
-		if err := validateApplicationsFile(filePath); err != nil {
-			log.Printf("WARNING: Applications file contains invalid JSON: %v", err)
-			log.Printf("You may want to fix or recreate the file to avoid runtime errors")
-			// We don't return an error here to allow the application to start,
-			// but we log a warning so the user knows there might be issues
-		}
+	if err := backfillDates(context.Background()); err != nil {
+		slog.Warn("failed to set applied dates and last activity", "error", err)
 	}
 
-	log.Printf("Storage initialization complete")
+	if err := normalizeStoredTags(context.Background()); err != nil {
+		slog.Warn("failed to normalize application tags", "error", err)
+	}
+
+	if err := runMigration(context.Background(), migrationWorkModeTags, migrateWorkModeTags); err != nil {
+		slog.Warn("failed to move work mode tags to work modes", "error", err)
+	}
+
+	if err := assignCompanies(context.Background()); err != nil {
+		slog.Warn("failed to assign applications to companies", "error", err)
+	}
+
+	slog.Info("storage initialization complete")
 	return nil
 }
 
+// backfillDates sets the applied date of applications logged before it existed to when
+// they were logged, and their last activity to their last update
+func backfillDates(ctx context.Context) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for i, app := range applications {
+		if !app.AppliedAt.IsZero() && !app.LastActivityAt.IsZero() {
+			continue
+		}
+		if app.AppliedAt.IsZero() {
+			applications[i].AppliedAt = app.CreatedAt
+		}
+		if app.LastActivityAt.IsZero() {
+			applications[i].LastActivityAt = app.UpdatedAt
+		}
+		changed++
+	}
+	if changed == 0 {
+		return nil
+	}
+
+	slog.InfoContext(ctx, "set applied dates and last activity of older applications", "applications", changed)
+	return saveApplicationsToFile(ctx, applications)
+}
+
 // validateApplicationsFile checks if the applications file contains valid JSON
 func validateApplicationsFile(filePath string) error {
 	data, err := os.ReadFile(filePath)
@@ -88,53 +138,130 @@ func validateApplicationsFile(filePath string) error {
 		return fmt.Errorf("invalid JSON in applications file: %w", err)
 	}
 
+	recordStats(data, applications)
 	return nil
 }
 
-// GetAllApplications returns all applications
-func GetAllApplications() ([]models.Application, error) {
-	log.Printf("Getting all applications")
+// DataDir returns the directory where data files are stored
+func DataDir() string {
+	return dataDir
+}
+
+// CheckWritable verifies that files can be created in the data directory
+func CheckWritable() error {
+	f, err := os.CreateTemp(dataDir, ".healthcheck-*")
+	if err != nil {
+		return fmt.Errorf("data directory is not writable: %w", err)
+	}
+	name := f.Name()
+	defer os.Remove(name)
+
+	if _, err := f.Write([]byte("ok")); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write to data directory: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write to data directory: %w", err)
+	}
+	return nil
+}
+
+// ValidateApplications checks that the applications file can be read and parsed
+func ValidateApplications() error {
 	mutex.RLock()
 	defer mutex.RUnlock()
 
-	filePath := filepath.Join(dataDir, applicationsFile)
-	data, err := os.ReadFile(filePath)
+	return validateApplicationsFile(filepath.Join(dataDir, applicationsFile))
+}
+
+// GetAllApplications returns all applications owned by ownerID
+func GetAllApplications(ctx context.Context, ownerID string) ([]models.Application, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterByOwner(applications, ownerID), nil
+}
+
+// filterByOwner returns the applications owned by ownerID
+func filterByOwner(applications []models.Application, ownerID string) []models.Application {
+	owned := []models.Application{}
+	for _, app := range applications {
+		if app.OwnerID == ownerID {
+			owned = append(owned, app)
+		}
+	}
+	return owned
+}
+
+// ClaimUnownedApplications assigns applications created before accounts existed to ownerID
+func ClaimUnownedApplications(ctx context.Context, ownerID string) (int, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return claimUnowned(ctx, ownerID)
+}
+
+// claimUnowned assigns unowned applications to ownerID; callers must hold the mutex
+func claimUnowned(ctx context.Context, ownerID string) (int, error) {
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	claimed := 0
+	for i := range applications {
+		if applications[i].OwnerID == "" {
+			applications[i].OwnerID = ownerID
+			claimed++
+		}
+	}
+	if claimed == 0 {
+		return 0, nil
+	}
 
-	log.Printf("Reading applications file: %s", filePath)
+	slog.InfoContext(ctx, "assigned unowned applications", "userId", ownerID, "count", claimed)
+	return claimed, saveApplicationsToFile(ctx, applications)
+}
 
+// readApplicationsFile loads the applications file; callers must hold the mutex
+func readApplicationsFile(ctx context.Context) ([]models.Application, error) {
+	filePath := filepath.Join(dataDir, applicationsFile)
+	start := time.Now()
+	data, err := os.ReadFile(filePath)
+	metrics.ObserveStorage("read", start)
 	if err != nil {
-		log.Printf("ERROR: Failed to read applications file: %v", err)
+		slog.ErrorContext(ctx, "failed to read applications file", "file", filePath, "error", err)
 		return nil, fmt.Errorf("failed to read applications file: %w", err)
 	}
 
 	var applications []models.Application
 	if err := json.Unmarshal(data, &applications); err != nil {
-		// This is a critical error - log it with details
-		log.Printf("ERROR: Failed to unmarshal applications JSON: %v", err)
-		// Include a snippet of the problematic JSON in the log
-		if len(data) > 100 {
-			log.Printf("JSON snippet (first 100 bytes): %s", string(data[:100]))
-		} else {
-			log.Printf("JSON content: %s", string(data))
-		}
+		// Only the size is logged; the file content is personal data
+		slog.ErrorContext(ctx, "failed to unmarshal applications file",
+			"file", filePath, "bytes", len(data), "error", err)
 		return nil, fmt.Errorf("failed to unmarshal applications: %w", err)
 	}
 
-	log.Printf("Loaded applications from file: %s", filePath)
-	log.Printf("Loaded %d applications from JSON", len(applications))
-
+	recordStats(data, applications)
+	slog.DebugContext(ctx, "loaded applications", "file", filePath, "count", len(applications))
 	return applications, nil
 }
 
-// GetPaginatedApplications returns a paginated list of applications
-func GetPaginatedApplications(page, pageSize int) ([]models.Application, int, error) {
-	log.Printf("Getting paginated applications: page=%d, pageSize=%d", page, pageSize)
+// GetPaginatedApplications returns a paginated list of the applications owned by ownerID,
+// most recently applied first
+func GetPaginatedApplications(ctx context.Context, ownerID string, page, pageSize int) ([]models.Application, int, error) {
+	slog.DebugContext(ctx, "getting paginated applications", "page", page, "pageSize", pageSize)
 
 	// Get all applications
-	applications, err := GetAllApplications()
+	applications, err := GetAllApplications(ctx, ownerID)
 	if err != nil {
 		return nil, 0, err
 	}
+	models.SortByApplied(applications)
 
 	// Calculate total count
 	totalCount := len(applications)
@@ -157,47 +284,48 @@ func GetPaginatedApplications(page, pageSize int) ([]models.Application, int, er
 	return applications[startIndex:endIndex], totalCount, nil
 }
 
-// GetApplicationByID returns an application by ID
-func GetApplicationByID(id string) (*models.Application, error) {
-	log.Printf("Getting application by ID: %s", id)
-
-	applications, err := GetAllApplications()
+// GetApplicationByID returns an application by ID; applications owned by someone else are not found
+func GetApplicationByID(ctx context.Context, ownerID, id string) (*models.Application, error) {
+	applications, err := GetAllApplications(ctx, ownerID)
 	if err != nil {
-		log.Printf("ERROR: Failed to get applications when looking up by ID: %v", err)
 		return nil, err
 	}
 
 	for _, app := range applications {
 		if app.ID == id {
-			log.Printf("Found application: %s - %s (ID: %s)", app.Company, app.Position, app.ID)
 			return &app, nil
 		}
 	}
 
-	log.Printf("ERROR: Application with ID %s not found", id)
+	slog.DebugContext(ctx, "application not found", "id", id)
 	return nil, ErrNotFound
 }
 
-// SaveApplication saves an application (creates or updates)
-func SaveApplication(app *models.Application) error {
-	log.Printf("Saving application: %s - %s", app.Company, app.ID)
+// SaveApplication saves an application (creates or updates); an existing application
+// can only be replaced by one with the same owner
+func SaveApplication(ctx context.Context, app *models.Application) error {
+	if app.OwnerID == "" {
+		return ErrNoOwner
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
 
-	applications, err := GetAllApplications()
+	applications, err := readApplicationsFile(ctx)
 	if err != nil {
-		log.Printf("ERROR: Failed to get applications for saving: %v", err)
 		return err
 	}
 
-	log.Printf("Retrieved %d existing applications for update", len(applications))
-
 	// Check if application already exists
 	found := false
 	for i, a := range applications {
 		if a.ID == app.ID {
+			if a.OwnerID != app.OwnerID {
+				return ErrNotFound
+			}
 			// Update existing application
 			applications[i] = *app
 			found = true
-			log.Printf("Updated existing application: %s (ID: %s)", app.Company, app.ID)
 			break
 		}
 	}
@@ -205,20 +333,21 @@ func SaveApplication(app *models.Application) error {
 	// Add new application if not found
 	if !found {
 		applications = append(applications, *app)
-		log.Printf("Added new application: %s (ID: %s)", app.Company, app.ID)
 	}
 
+	slog.DebugContext(ctx, "saving application", "id", app.ID, "created", !found)
+
 	// Save to file
-	return saveApplicationsToFile(applications)
+	return saveApplicationsToFile(ctx, applications)
 }
 
-// DeleteApplication deletes an application by ID
-func DeleteApplication(id string) error {
-	log.Printf("Deleting application with ID: %s", id)
+// DeleteApplication deletes an application by ID if it is owned by ownerID
+func DeleteApplication(ctx context.Context, ownerID, id string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
 
-	applications, err := GetAllApplications()
+	applications, err := readApplicationsFile(ctx)
 	if err != nil {
-		log.Printf("ERROR: Failed to get applications for deletion: %v", err)
 		return err
 	}
 
@@ -226,34 +355,230 @@ func DeleteApplication(id string) error {
 	var updatedApps []models.Application
 
 	for _, app := range applications {
-		if app.ID != id {
+		if app.ID != id || app.OwnerID != ownerID {
 			updatedApps = append(updatedApps, app)
 		} else {
 			found = true
-			log.Printf("Found application to delete: %s - %s", app.Company, app.ID)
 		}
 	}
 
 	if !found {
-		log.Printf("ERROR: Application with ID %s not found for deletion", id)
+		slog.DebugContext(ctx, "application not found for deletion", "id", id)
 		return ErrNotFound
 	}
 
-	log.Printf("Deleting application with ID %s (removed from %d total applications)", 
-		id, len(applications))
-	return saveApplicationsToFile(updatedApps)
+	slog.DebugContext(ctx, "deleting application", "id", id)
+	if err := saveApplicationsToFile(ctx, updatedApps); err != nil {
+		return err
+	}
+
+	deleteRelated(ctx, ownerID, id)
+	return nil
+}
+
+// deleteRelated removes the comments, notes, interviews and reminders of a deleted
+// application and unlinks its contacts. Failures are logged rather than returned since
+// the application itself is gone.
+func deleteRelated(ctx context.Context, ownerID, id string) {
+	if err := deleteComments(ctx, ownerID, id); err != nil {
+		slog.WarnContext(ctx, "failed to delete comments of deleted application", "id", id, "error", err)
+	}
+	if err := deleteNotes(ctx, ownerID, id); err != nil {
+		slog.WarnContext(ctx, "failed to delete notes of deleted application", "id", id, "error", err)
+	}
+	if err := deleteInterviews(ctx, ownerID, id); err != nil {
+		slog.WarnContext(ctx, "failed to delete interviews of deleted application", "id", id, "error", err)
+	}
+	if err := relinkContacts(ctx, ownerID, id, ""); err != nil {
+		slog.WarnContext(ctx, "failed to unlink contacts of deleted application", "id", id, "error", err)
+	}
+	if err := deleteReminders(ctx, ownerID, id); err != nil {
+		slog.WarnContext(ctx, "failed to delete reminders of deleted application", "id", id, "error", err)
+	}
+}
+
+// moveRelated moves the comments, notes, interviews, contacts and reminders of a merged
+// application to the application it was merged into
+func moveRelated(ctx context.Context, ownerID, fromID, toID string) {
+	if err := moveComments(ctx, ownerID, fromID, toID); err != nil {
+		slog.WarnContext(ctx, "failed to move comments of merged application", "id", fromID, "error", err)
+	}
+	if err := moveNotes(ctx, ownerID, fromID, toID); err != nil {
+		slog.WarnContext(ctx, "failed to move notes of merged application", "id", fromID, "error", err)
+	}
+	if err := moveInterviews(ctx, ownerID, fromID, toID); err != nil {
+		slog.WarnContext(ctx, "failed to move interviews of merged application", "id", fromID, "error", err)
+	}
+	if err := relinkContacts(ctx, ownerID, fromID, toID); err != nil {
+		slog.WarnContext(ctx, "failed to move contacts of merged application", "id", fromID, "error", err)
+	}
+	if err := moveReminders(ctx, ownerID, fromID, toID); err != nil {
+		slog.WarnContext(ctx, "failed to move reminders of merged application", "id", fromID, "error", err)
+	}
+}
+
+// FindDuplicates returns the applications owned by ownerID that are likely duplicates of app
+func FindDuplicates(ctx context.Context, ownerID string, app *models.Application) ([]models.Application, error) {
+	applications, err := GetAllApplications(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := []models.Application{}
+	for _, candidate := range applications {
+		if candidate.ID != app.ID && models.IsLikelyDuplicate(*app, candidate) {
+			duplicates = append(duplicates, candidate)
+		}
+	}
+	return duplicates, nil
 }
 
-// SearchApplications searches applications by tags and text
-func SearchApplications(query string, tags []string) ([]models.Application, error) {
-	log.Printf("Searching applications with query: '%s', tags: %v", query, tags)
+// MergeApplications folds the application duplicateID into keepID, moves its related records
+// over and deletes it. It returns the merged application and the deleted duplicate, or
+// ErrTooManyTags if the combined tags would exceed maxTags.
+func MergeApplications(ctx context.Context, ownerID, keepID, duplicateID string, maxTags int) (*models.Application, *models.Application, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keep, duplicate := -1, -1
+	for i, app := range applications {
+		if app.OwnerID != ownerID {
+			continue
+		}
+		switch app.ID {
+		case keepID:
+			keep = i
+		case duplicateID:
+			duplicate = i
+		}
+	}
+	if keep < 0 || duplicate < 0 {
+		return nil, nil, ErrNotFound
+	}
+
+	merged := applications[keep]
+	removed := applications[duplicate]
+	merged.Merge(removed)
+	if len(merged.Tags) > maxTags {
+		return nil, nil, ErrTooManyTags
+	}
+	applications[keep] = merged
+	applications = append(applications[:duplicate], applications[duplicate+1:]...)
+
+	slog.DebugContext(ctx, "merging applications", "id", keepID, "duplicateId", duplicateID)
+	if err := saveApplicationsToFile(ctx, applications); err != nil {
+		return nil, nil, err
+	}
+
+	moveRelated(ctx, ownerID, duplicateID, keepID)
+	return &merged, &removed, nil
+}
+
+// ApplyBulkOperations applies operations to the applications owned by ownerID in order
+// and saves them with a single write. If any operation fails nothing is saved and the
+// results say which ones failed. Adding a tag fails once an application has maxTags tags.
+func ApplyBulkOperations(ctx context.Context, ownerID string, operations []models.BulkOperation, maxTags int) ([]models.BulkResult, bool, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	index := make(map[string]int)
+	for i, app := range applications {
+		if app.OwnerID == ownerID {
+			index[app.ID] = i
+		}
+	}
+
+	deleted := make(map[string]bool)
+	results := make([]models.BulkResult, len(operations))
+	ok := true
+	for i, op := range operations {
+		result := models.BulkResult{Index: i, ID: op.ID, Action: op.Action}
+		pos, found := index[op.ID]
+		if !found || deleted[op.ID] {
+			result.Error = "application not found"
+			results[i] = result
+			ok = false
+			continue
+		}
+
+		app := &applications[pos]
+		switch op.Action {
+		case models.BulkSetStatus:
+			result.PreviousStatus = app.Status
+			app.UpdateStatus(op.Status)
+		case models.BulkAddTag:
+			if !app.HasTag(op.Tag) && len(app.Tags) >= maxTags {
+				result.Error = fmt.Sprintf("an application can have at most %d tags", maxTags)
+			} else {
+				app.AddTag(op.Tag)
+			}
+		case models.BulkRemoveTag:
+			app.RemoveTag(op.Tag)
+		case models.BulkDelete:
+			deleted[op.ID] = true
+		}
+
+		if result.Error != "" {
+			ok = false
+		} else {
+			result.Success = true
+			if op.Action != models.BulkDelete {
+				app.RecordActivity()
+				snapshot := *app
+				snapshot.Tags = slices.Clone(app.Tags)
+				result.Application = &snapshot
+			}
+		}
+		results[i] = result
+	}
+	if !ok {
+		return results, false, nil
+	}
+
+	remaining := applications[:0]
+	for _, app := range applications {
+		if !(app.OwnerID == ownerID && deleted[app.ID]) {
+			remaining = append(remaining, app)
+		}
+	}
+
+	slog.DebugContext(ctx, "applying bulk operations", "operations", len(operations), "deleted", len(deleted))
+	if err := saveApplicationsToFile(ctx, remaining); err != nil {
+		return nil, false, err
+	}
+
+	for id := range deleted {
+		deleteRelated(ctx, ownerID, id)
+	}
+	return results, true, nil
+}
 
-	applications, err := GetAllApplications()
+// SearchApplications searches the applications owned by ownerID by tags and text. With
+// includeNotes the text also matches the applications' notes. Results are ordered
+// most recently applied first.
+func SearchApplications(ctx context.Context, ownerID, query string, tags []string, includeNotes bool) ([]models.Application, error) {
+	applications, err := GetAllApplications(ctx, ownerID)
 	if err != nil {
-		log.Printf("ERROR: Failed to get applications for search: %v", err)
 		return nil, err
 	}
 
+	var notes map[string]string
+	if query != "" && includeNotes {
+		if notes, err = noteText(ctx, ownerID); err != nil {
+			return nil, err
+		}
+	}
+
 	var results []models.Application
 
 	for _, app := range applications {
@@ -263,7 +588,7 @@ func SearchApplications(query string, tags []string) ([]models.Application, erro
 			for _, searchTag := range tags {
 				tagFound := false
 				for _, appTag := range app.Tags {
-					if strings.EqualFold(appTag, searchTag) {
+					if strings.EqualFold(appTag, models.NormalizeTag(searchTag)) {
 						tagFound = true
 						break
 					}
@@ -283,7 +608,8 @@ func SearchApplications(query string, tags []string) ([]models.Application, erro
 			query = strings.ToLower(query)
 			if !strings.Contains(strings.ToLower(app.Company), query) &&
 				!strings.Contains(strings.ToLower(app.Position), query) &&
-				!strings.Contains(strings.ToLower(app.Description), query) {
+				!strings.Contains(strings.ToLower(app.Description), query) &&
+				!strings.Contains(notes[app.ID], query) {
 				continue
 			}
 		}
@@ -291,33 +617,48 @@ func SearchApplications(query string, tags []string) ([]models.Application, erro
 		results = append(results, app)
 	}
 
-	log.Printf("Search returned %d results", len(results))
+	models.SortByApplied(results)
+	slog.DebugContext(ctx, "searched applications", "tagCount", len(tags), "results", len(results))
 	return results, nil
 }
 
-// saveApplicationsToFile saves applications to the JSON file
-func saveApplicationsToFile(applications []models.Application) error {
+// saveApplicationsToFile saves applications to the JSON file; callers must hold the mutex
+func saveApplicationsToFile(ctx context.Context, applications []models.Application) error {
 	filePath := filepath.Join(dataDir, applicationsFile)
-	log.Printf("Saving %d applications to file: %s", len(applications), filePath)
 
 	jsonData, err := json.MarshalIndent(applications, "", "  ")
 	if err != nil {
-		log.Printf("ERROR: Failed to marshal applications to JSON: %v", err)
+		slog.ErrorContext(ctx, "failed to marshal applications", "error", err)
 		return fmt.Errorf("failed to marshal applications: %w", err)
 	}
 
-	// Log a snippet of the JSON data for debugging
-	if len(jsonData) > 100 {
-		log.Printf("JSON data snippet (first 100 bytes): %s", string(jsonData[:100]))
-	} else {
-		log.Printf("JSON data: %s", string(jsonData))
-	}
-
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
-		log.Printf("ERROR: Failed to write applications file: %v", err)
+	start := time.Now()
+	err = os.WriteFile(filePath, jsonData, 0644)
+	metrics.ObserveStorage("write", start)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to write applications file", "file", filePath, "error", err)
 		return fmt.Errorf("failed to write applications file: %w", err)
 	}
 
-	log.Printf("Successfully saved applications to file")
+	recordStats(jsonData, applications)
+
+	slog.DebugContext(ctx, "saved applications", "file", filePath, "count", len(applications), "bytes", len(jsonData))
 	return nil
 }
+
+// recordStats publishes the file size, per-status counts and stale count of the stored
+// applications
+func recordStats(data []byte, applications []models.Application) {
+	metrics.SetStorageFileSize(applicationsFile, len(data))
+
+	counts := make(map[string]int)
+	stale := 0
+	for _, app := range applications {
+		counts[app.Status]++
+		if app.IsStale() {
+			stale++
+		}
+	}
+	metrics.SetApplicationCounts(counts)
+	metrics.SetStaleApplications(stale)
+}
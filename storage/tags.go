@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+const tagColorsFile = "tags.json"
+
+// tagColorsMutex guards the tag colors file
+var tagColorsMutex = &sync.RWMutex{}
+
+// readTagColors loads the colors of all tags; callers must hold tagColorsMutex
+func readTagColors(ctx context.Context) ([]models.TagColor, error) {
+	colors := []models.TagColor{}
+	if err := readJSONFile(ctx, tagColorsFile, &colors); err != nil {
+		return nil, err
+	}
+	return colors, nil
+}
+
+// TagColors returns the colors chosen for tags in a workspace, by tag name
+func TagColors(ctx context.Context, workspaceID string) (map[string]string, error) {
+	tagColorsMutex.RLock()
+	defer tagColorsMutex.RUnlock()
+
+	colors, err := readTagColors(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for _, c := range colors {
+		if c.WorkspaceID == workspaceID {
+			result[c.Name] = c.Color
+		}
+	}
+	return result, nil
+}
+
+// SetTagColor chooses the color of a tag in a workspace
+func SetTagColor(ctx context.Context, workspaceID, name, color string) error {
+	tagColorsMutex.Lock()
+	defer tagColorsMutex.Unlock()
+
+	colors, err := readTagColors(ctx)
+	if err != nil {
+		return err
+	}
+
+	name = models.NormalizeTag(name)
+	for i, c := range colors {
+		if c.WorkspaceID == workspaceID && c.Name == name {
+			colors[i].Color = color
+			return writeJSONFile(ctx, tagColorsFile, colors)
+		}
+	}
+	return writeJSONFile(ctx, tagColorsFile, append(colors, models.TagColor{WorkspaceID: workspaceID, Name: name, Color: color}))
+}
+
+// ListTags returns the tags used by a workspace's applications with their usage counts
+// and colors, sorted by name
+func ListTags(ctx context.Context, workspaceID string) ([]models.Tag, error) {
+	applications, err := GetAllApplications(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	colors, err := TagColors(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, app := range applications {
+		for _, tag := range app.Tags {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]models.Tag, 0, len(counts))
+	for name, count := range counts {
+		color := colors[name]
+		if color == "" {
+			color = models.DefaultTagColor
+		}
+		tags = append(tags, models.Tag{Name: name, Color: color, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// ReplaceTags replaces the tags in from with the tag to on every application of a
+// workspace in a single write, which renames a tag or merges several into one. The
+// color of the first source tag carries over if to has none. It returns the number of
+// applications changed.
+func ReplaceTags(ctx context.Context, workspaceID string, from []string, to string) (int, error) {
+	to = models.NormalizeTag(to)
+	sources := models.NormalizeTags(from)
+
+	mutex.Lock()
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		mutex.Unlock()
+		return 0, err
+	}
+
+	changed := 0
+	now := time.Now()
+	for i, app := range applications {
+		if app.OwnerID != workspaceID {
+			continue
+		}
+		tags := []string{}
+		replaced := false
+		for _, tag := range app.Tags {
+			if slices.Contains(sources, tag) {
+				tag = to
+				replaced = true
+			}
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		if replaced {
+			applications[i].Tags = tags
+			applications[i].UpdatedAt = now
+			changed++
+		}
+	}
+
+	if changed > 0 {
+		slog.DebugContext(ctx, "replacing tags", "tags", len(sources), "applications", changed)
+		err = saveApplicationsToFile(ctx, applications)
+	}
+	mutex.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := moveTagColor(ctx, workspaceID, sources, to); err != nil {
+		slog.WarnContext(ctx, "failed to update tag colors", "error", err)
+	}
+	return changed, nil
+}
+
+// moveTagColor drops the colors of replaced tags, giving the first of them to the new tag if it has none
+func moveTagColor(ctx context.Context, workspaceID string, from []string, to string) error {
+	tagColorsMutex.Lock()
+	defer tagColorsMutex.Unlock()
+
+	colors, err := readTagColors(ctx)
+	if err != nil {
+		return err
+	}
+
+	inherited, inheritedIndex := "", len(from)
+	hasColor := false
+	kept := []models.TagColor{}
+	for _, c := range colors {
+		if c.WorkspaceID == workspaceID && c.Name == to {
+			hasColor = true
+		} else if i := slices.Index(from, c.Name); c.WorkspaceID == workspaceID && i >= 0 {
+			if i < inheritedIndex {
+				inherited, inheritedIndex = c.Color, i
+			}
+			continue
+		}
+		kept = append(kept, c)
+	}
+	if len(kept) == len(colors) {
+		return nil
+	}
+	if !hasColor && inherited != "" {
+		kept = append(kept, models.TagColor{WorkspaceID: workspaceID, Name: to, Color: inherited})
+	}
+	return writeJSONFile(ctx, tagColorsFile, kept)
+}
+
+// normalizeStoredTags normalizes the tags of applications saved before tags were
+// normalized, merging tags that differ only by case or whitespace
+func normalizeStoredTags(ctx context.Context) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for i, app := range applications {
+		normalized := models.NormalizeTags(app.Tags)
+		if !slices.Equal(normalized, app.Tags) {
+			applications[i].Tags = normalized
+			changed++
+		}
+	}
+	if changed == 0 {
+		return nil
+	}
+
+	slog.InfoContext(ctx, "normalized application tags", "applications", changed)
+	return saveApplicationsToFile(ctx, applications)
+}
+
+// migrateWorkModeTags sets the work mode of applications tagged with one before work
+// modes existed, such as "remote" or "on-site"; it runs once, through runMigration
+func migrateWorkModeTags(ctx context.Context) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	applications, err := readApplicationsFile(ctx)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for i := range applications {
+		if applications[i].SetWorkModeFromTags() {
+			changed++
+		}
+	}
+	if changed == 0 {
+		return nil
+	}
+
+	slog.InfoContext(ctx, "set work modes from tags", "applications", changed)
+	return saveApplicationsToFile(ctx, applications)
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+const tokensFile = "tokens.json"
+
+var (
+	// ErrTokenNotFound is returned when an API token is not found
+	ErrTokenNotFound = errors.New("token not found")
+
+	// tokensMutex guards the tokens file
+	tokensMutex = &sync.RWMutex{}
+)
+
+// readTokens loads all API tokens; callers must hold tokensMutex
+func readTokens(ctx context.Context) ([]models.APIToken, error) {
+	tokens := []models.APIToken{}
+	if err := readJSONFile(ctx, tokensFile, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// ListTokens returns the API tokens belonging to a user
+func ListTokens(ctx context.Context, userID string) ([]models.APIToken, error) {
+	tokensMutex.RLock()
+	defer tokensMutex.RUnlock()
+
+	tokens, err := readTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := []models.APIToken{}
+	for _, token := range tokens {
+		if token.UserID == userID {
+			owned = append(owned, token)
+		}
+	}
+	return owned, nil
+}
+
+// CreateToken stores a new API token
+func CreateToken(ctx context.Context, token *models.APIToken) error {
+	tokensMutex.Lock()
+	defer tokensMutex.Unlock()
+
+	tokens, err := readTokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "creating API token", "tokenId", token.ID, "userId", token.UserID, "scopes", token.Scopes)
+	return writeJSONFile(ctx, tokensFile, append(tokens, *token))
+}
+
+// GetTokenByHash returns the API token whose secret hashes to tokenHash
+func GetTokenByHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	tokensMutex.RLock()
+	defer tokensMutex.RUnlock()
+
+	tokens, err := readTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, ErrTokenNotFound
+}
+
+// RevokeToken marks a user's API token as revoked
+func RevokeToken(ctx context.Context, userID, id string) (*models.APIToken, error) {
+	return updateToken(ctx, userID, id, func(token *models.APIToken) {
+		if token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+		}
+	})
+}
+
+// TouchToken records that an API token was used at the given time
+func TouchToken(ctx context.Context, userID, id string, usedAt time.Time) error {
+	_, err := updateToken(ctx, userID, id, func(token *models.APIToken) {
+		token.LastUsedAt = &usedAt
+	})
+	return err
+}
+
+// updateToken applies fn to a user's token and saves the result
+func updateToken(ctx context.Context, userID, id string, fn func(*models.APIToken)) (*models.APIToken, error) {
+	tokensMutex.Lock()
+	defer tokensMutex.Unlock()
+
+	tokens, err := readTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tokens {
+		if tokens[i].ID == id && tokens[i].UserID == userID {
+			fn(&tokens[i])
+			if err := writeJSONFile(ctx, tokensFile, tokens); err != nil {
+				return nil, err
+			}
+			return &tokens[i], nil
+		}
+	}
+	return nil, ErrTokenNotFound
+}
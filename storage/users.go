@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+const (
+	usersFile    = "users.json"
+	sessionsFile = "sessions.json"
+)
+
+var (
+	// ErrUserNotFound is returned when a user is not found
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrUsernameTaken is returned when creating a user whose username already exists
+	ErrUsernameTaken = errors.New("username already taken")
+
+	// ErrSessionNotFound is returned when a session does not exist or has expired
+	ErrSessionNotFound = errors.New("session not found")
+
+	// authMutex guards the users and sessions files
+	authMutex = &sync.RWMutex{}
+)
+
+// readUsers loads all users; callers must hold authMutex
+func readUsers(ctx context.Context) ([]models.User, error) {
+	users := []models.User{}
+	if err := readJSONFile(ctx, usersFile, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// CountUsers returns the number of registered users
+func CountUsers(ctx context.Context) (int, error) {
+	authMutex.RLock()
+	defer authMutex.RUnlock()
+
+	users, err := readUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(users), nil
+}
+
+// GetUserByID returns a user by ID
+func GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	authMutex.RLock()
+	defer authMutex.RUnlock()
+
+	users, err := readUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if user.ID == id {
+			return &user, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+// GetUserByUsername returns a user by username, ignoring case
+func GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	authMutex.RLock()
+	defer authMutex.RUnlock()
+
+	users, err := readUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	username = models.NormalizeUsername(username)
+	for _, user := range users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+// CreateUser stores a new user, failing if the username is already taken. The first
+// user takes over applications tracked before accounts existed; both happen under the
+// locks so concurrent signups can't both be first.
+func CreateUser(ctx context.Context, user *models.User) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	authMutex.Lock()
+	defer authMutex.Unlock()
+
+	users, err := readUsers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, existing := range users {
+		if existing.Username == user.Username {
+			return ErrUsernameTaken
+		}
+	}
+
+	slog.InfoContext(ctx, "creating user", "userId", user.ID)
+	if err := writeJSONFile(ctx, usersFile, append(users, *user)); err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		if _, err := claimUnowned(ctx, user.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to assign unowned applications", "userId", user.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// claimForFirstUser assigns unowned applications to the earliest registered user, if any
+func claimForFirstUser(ctx context.Context) error {
+	authMutex.RLock()
+	users, err := readUsers(ctx)
+	authMutex.RUnlock()
+	if err != nil || len(users) == 0 {
+		return err
+	}
+
+	first := users[0]
+	for _, user := range users[1:] {
+		if user.CreatedAt.Before(first.CreatedAt) {
+			first = user
+		}
+	}
+	_, err = ClaimUnownedApplications(ctx, first.ID)
+	return err
+}
+
+// readSessions loads all unexpired sessions; callers must hold authMutex
+func readSessions(ctx context.Context) ([]models.Session, error) {
+	sessions := []models.Session{}
+	if err := readJSONFile(ctx, sessionsFile, &sessions); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	active := sessions[:0]
+	for _, session := range sessions {
+		if !session.Expired(now) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+// SaveSession stores a new session and prunes expired ones
+func SaveSession(ctx context.Context, session *models.Session) error {
+	authMutex.Lock()
+	defer authMutex.Unlock()
+
+	sessions, err := readSessions(ctx)
+	if err != nil {
+		return err
+	}
+	return writeJSONFile(ctx, sessionsFile, append(sessions, *session))
+}
+
+// GetSession returns the unexpired session with the given token hash
+func GetSession(ctx context.Context, tokenHash string) (*models.Session, error) {
+	authMutex.RLock()
+	defer authMutex.RUnlock()
+
+	sessions, err := readSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if session.TokenHash == tokenHash {
+			return &session, nil
+		}
+	}
+	return nil, ErrSessionNotFound
+}
+
+// DeleteSession removes the session with the given token hash
+func DeleteSession(ctx context.Context, tokenHash string) error {
+	authMutex.Lock()
+	defer authMutex.Unlock()
+
+	sessions, err := readSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := sessions[:0]
+	for _, session := range sessions {
+		if session.TokenHash != tokenHash {
+			remaining = append(remaining, session)
+		}
+	}
+	return writeJSONFile(ctx, sessionsFile, remaining)
+}
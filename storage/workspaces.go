@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"ApplicationTracker/models"
+)
+
+const membershipsFile = "memberships.json"
+
+var (
+	// ErrMembershipNotFound is returned when a user is not a member of a workspace
+	ErrMembershipNotFound = errors.New("membership not found")
+
+	// membershipsMutex guards the memberships file
+	membershipsMutex = &sync.RWMutex{}
+)
+
+// readMemberships loads all memberships; callers must hold membershipsMutex
+func readMemberships(ctx context.Context) ([]models.Membership, error) {
+	memberships := []models.Membership{}
+	if err := readJSONFile(ctx, membershipsFile, &memberships); err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
+// ListMembers returns the members of a workspace
+func ListMembers(ctx context.Context, workspaceID string) ([]models.Membership, error) {
+	membershipsMutex.RLock()
+	defer membershipsMutex.RUnlock()
+
+	memberships, err := readMemberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []models.Membership{}
+	for _, m := range memberships {
+		if m.WorkspaceID == workspaceID {
+			members = append(members, m)
+		}
+	}
+	return members, nil
+}
+
+// ListMembershipsForUser returns the workspaces a user has been added to
+func ListMembershipsForUser(ctx context.Context, userID string) ([]models.Membership, error) {
+	membershipsMutex.RLock()
+	defer membershipsMutex.RUnlock()
+
+	memberships, err := readMemberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Membership{}
+	for _, m := range memberships {
+		if m.UserID == userID {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// GetMembership returns a user's membership in a workspace
+func GetMembership(ctx context.Context, workspaceID, userID string) (*models.Membership, error) {
+	membershipsMutex.RLock()
+	defer membershipsMutex.RUnlock()
+
+	memberships, err := readMemberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range memberships {
+		if m.WorkspaceID == workspaceID && m.UserID == userID {
+			return &m, nil
+		}
+	}
+	return nil, ErrMembershipNotFound
+}
+
+// SaveMembership adds a member to a workspace, or changes the role of an existing member;
+// it reports whether the membership was created
+func SaveMembership(ctx context.Context, membership *models.Membership) (bool, error) {
+	membershipsMutex.Lock()
+	defer membershipsMutex.Unlock()
+
+	memberships, err := readMemberships(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for i, m := range memberships {
+		if m.WorkspaceID == membership.WorkspaceID && m.UserID == membership.UserID {
+			memberships[i].Role = membership.Role
+			memberships[i].UpdatedAt = time.Now()
+			*membership = memberships[i]
+			slog.InfoContext(ctx, "changing member role", "workspaceId", membership.WorkspaceID, "userId", membership.UserID, "role", membership.Role)
+			return false, writeJSONFile(ctx, membershipsFile, memberships)
+		}
+	}
+
+	slog.InfoContext(ctx, "adding workspace member", "workspaceId", membership.WorkspaceID, "userId", membership.UserID, "role", membership.Role)
+	return true, writeJSONFile(ctx, membershipsFile, append(memberships, *membership))
+}
+
+// DeleteMembership removes a user from a workspace
+func DeleteMembership(ctx context.Context, workspaceID, userID string) (*models.Membership, error) {
+	membershipsMutex.Lock()
+	defer membershipsMutex.Unlock()
+
+	memberships, err := readMemberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed *models.Membership
+	remaining := []models.Membership{}
+	for _, m := range memberships {
+		if m.WorkspaceID == workspaceID && m.UserID == userID {
+			removed = &m
+			continue
+		}
+		remaining = append(remaining, m)
+	}
+	if removed == nil {
+		return nil, ErrMembershipNotFound
+	}
+
+	slog.InfoContext(ctx, "removing workspace member", "workspaceId", workspaceID, "userId", userID)
+	return removed, writeJSONFile(ctx, membershipsFile, remaining)
+}
@@ -6,6 +6,15 @@
 # Set the API base URL
 API_URL="http://localhost:8080/api"
 
+# The API requires authentication; create a token with the applications:write
+# scope on the Settings page and export it as API_TOKEN
+if [ -z "$API_TOKEN" ]; then
+  echo "API_TOKEN is not set. Create a token on the Settings page and run:"
+  echo "  API_TOKEN=at_... $0"
+  exit 1
+fi
+AUTH_HEADER="Authorization: Bearer $API_TOKEN"
+
 # Colors for output
 GREEN='\033[0;32m'
 RED='\033[0;31m'
@@ -24,12 +33,12 @@ echo "Starting API tests..."
 
 # Test health endpoint
 echo -e "\n--- Testing Health Endpoint ---"
-response=$(curl -s -o /dev/null -w "%{http_code}" $API_URL/health)
+response=$(curl -s -H "$AUTH_HEADER" -o /dev/null -w "%{http_code}" $API_URL/health)
 print_result $? "Health check returned status code: $response"
 
 # Create a new application
 echo -e "\n--- Testing Create Application ---"
-create_response=$(curl -s -X POST $API_URL/applications \
+create_response=$(curl -s -H "$AUTH_HEADER" -X POST $API_URL/applications \
   -H "Content-Type: application/json" \
   -d '{
     "company": "Example Corp",
@@ -45,12 +54,12 @@ print_result $? "Created application with ID: $app_id"
 
 # Get all applications
 echo -e "\n--- Testing Get All Applications ---"
-get_all_response=$(curl -s $API_URL/applications)
+get_all_response=$(curl -s -H "$AUTH_HEADER" $API_URL/applications)
 print_result $? "Retrieved all applications"
 
 # Test pagination
 echo -e "\n--- Testing Pagination ---"
-page_response=$(curl -s "$API_URL/applications?page=1&pageSize=10")
+page_response=$(curl -s -H "$AUTH_HEADER" "$API_URL/applications?page=1&pageSize=10")
 meta_exists=$(echo $page_response | grep -o '"meta":' | wc -l)
 if [ $meta_exists -eq 1 ]; then
   print_result 0 "Pagination metadata exists in response"
@@ -60,7 +69,7 @@ fi
 
 # Test different page sizes
 echo -e "\n--- Testing Different Page Sizes ---"
-page_size_response=$(curl -s "$API_URL/applications?page=1&pageSize=25")
+page_size_response=$(curl -s -H "$AUTH_HEADER" "$API_URL/applications?page=1&pageSize=25")
 page_size=$(echo $page_size_response | grep -o '"pageSize":25' | wc -l)
 if [ $page_size -eq 1 ]; then
   print_result 0 "Page size parameter works correctly"
@@ -70,12 +79,12 @@ fi
 
 # Get application by ID
 echo -e "\n--- Testing Get Application by ID ---"
-get_response=$(curl -s $API_URL/applications/$app_id)
+get_response=$(curl -s -H "$AUTH_HEADER" $API_URL/applications/$app_id)
 print_result $? "Retrieved application with ID: $app_id"
 
 # Update application
 echo -e "\n--- Testing Update Application ---"
-update_response=$(curl -s -X PUT $API_URL/applications/$app_id \
+update_response=$(curl -s -H "$AUTH_HEADER" -X PUT $API_URL/applications/$app_id \
   -H "Content-Type: application/json" \
   -d '{
     "status": "in_progress",
@@ -85,22 +94,22 @@ print_result $? "Updated application with ID: $app_id"
 
 # Search applications by tag
 echo -e "\n--- Testing Search Applications by Tag ---"
-search_response=$(curl -s "$API_URL/applications/search?tags=golang,remote")
+search_response=$(curl -s -H "$AUTH_HEADER" "$API_URL/applications/search?tags=golang,remote")
 print_result $? "Searched applications with tags: golang, remote"
 
 # Search applications by query
 echo -e "\n--- Testing Search Applications by Query ---"
-search_response=$(curl -s "$API_URL/applications/search?q=Software")
+search_response=$(curl -s -H "$AUTH_HEADER" "$API_URL/applications/search?q=Software")
 print_result $? "Searched applications with query: Software"
 
 # Delete application
 echo -e "\n--- Testing Delete Application ---"
-delete_response=$(curl -s -X DELETE $API_URL/applications/$app_id)
+delete_response=$(curl -s -H "$AUTH_HEADER" -X DELETE $API_URL/applications/$app_id)
 print_result $? "Deleted application with ID: $app_id"
 
 # Verify deletion
 echo -e "\n--- Verifying Deletion ---"
-get_deleted_response=$(curl -s -o /dev/null -w "%{http_code}" $API_URL/applications/$app_id)
+get_deleted_response=$(curl -s -H "$AUTH_HEADER" -o /dev/null -w "%{http_code}" $API_URL/applications/$app_id)
 if [ $get_deleted_response -eq 404 ]; then
   print_result 0 "Application was successfully deleted (404 Not Found)"
 else
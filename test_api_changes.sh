@@ -6,6 +6,15 @@
 # Set the API base URL
 API_URL="http://localhost:8080/api"
 
+# The API requires authentication; create a token with the applications:write
+# scope on the Settings page and export it as API_TOKEN
+if [ -z "$API_TOKEN" ]; then
+  echo "API_TOKEN is not set. Create a token on the Settings page and run:"
+  echo "  API_TOKEN=at_... $0"
+  exit 1
+fi
+AUTH_HEADER="Authorization: Bearer $API_TOKEN"
+
 # Colors for output
 GREEN='\033[0;32m'
 RED='\033[0;31m'
@@ -24,7 +33,7 @@ echo "Starting API tests for changes..."
 
 # Test 1: Create an application and verify UUID v4 format
 echo -e "\n--- Testing UUID v4 Format for Application ID ---"
-create_response=$(curl -s -X POST $API_URL/applications \
+create_response=$(curl -s -H "$AUTH_HEADER" -X POST $API_URL/applications \
   -H "Content-Type: application/json" \
   -d '{
     "company": "Test UUID Corp",
@@ -47,7 +56,7 @@ fi
 
 # Test 2: Create an application with empty URL
 echo -e "\n--- Testing Create Application with Empty URL ---"
-create_empty_url_response=$(curl -s -X POST $API_URL/applications \
+create_empty_url_response=$(curl -s -H "$AUTH_HEADER" -X POST $API_URL/applications \
   -H "Content-Type: application/json" \
   -d '{
     "company": "Empty URL Corp",
@@ -62,7 +71,7 @@ empty_url_app_id=$(echo $create_empty_url_response | grep -o '"id":"[^"]*' | cut
 print_result $? "Created application with empty URL, ID: $empty_url_app_id"
 
 # Get the application to verify empty URL
-get_empty_url_response=$(curl -s $API_URL/applications/$empty_url_app_id)
+get_empty_url_response=$(curl -s -H "$AUTH_HEADER" $API_URL/applications/$empty_url_app_id)
 url_value=$(echo $get_empty_url_response | grep -o '"url":"[^"]*' | cut -d'"' -f4)
 
 if [ "$url_value" = "" ]; then
@@ -73,7 +82,7 @@ fi
 
 # Test 3: Update an application to have empty URL
 echo -e "\n--- Testing Update Application to Empty URL ---"
-update_response=$(curl -s -X PUT $API_URL/applications/$app_id \
+update_response=$(curl -s -H "$AUTH_HEADER" -X PUT $API_URL/applications/$app_id \
   -H "Content-Type: application/json" \
   -d '{
     "url": ""
@@ -81,7 +90,7 @@ update_response=$(curl -s -X PUT $API_URL/applications/$app_id \
 print_result $? "Updated application with ID: $app_id to have empty URL"
 
 # Get the application to verify empty URL after update
-get_updated_response=$(curl -s $API_URL/applications/$app_id)
+get_updated_response=$(curl -s -H "$AUTH_HEADER" $API_URL/applications/$app_id)
 updated_url_value=$(echo $get_updated_response | grep -o '"url":"[^"]*' | cut -d'"' -f4)
 
 if [ "$updated_url_value" = "" ]; then
@@ -92,10 +101,10 @@ fi
 
 # Clean up - Delete the applications
 echo -e "\n--- Cleaning Up ---"
-curl -s -X DELETE $API_URL/applications/$app_id > /dev/null
+curl -s -H "$AUTH_HEADER" -X DELETE $API_URL/applications/$app_id > /dev/null
 print_result $? "Deleted application with ID: $app_id"
 
-curl -s -X DELETE $API_URL/applications/$empty_url_app_id > /dev/null
+curl -s -H "$AUTH_HEADER" -X DELETE $API_URL/applications/$empty_url_app_id > /dev/null
 print_result $? "Deleted application with ID: $empty_url_app_id"
 
 echo -e "\nAPI tests for changes completed."
\ No newline at end of file
@@ -32,6 +32,28 @@ test.describe('API Endpoints', () => {
       expect(data.status).toBe('ok');
     });
 
+    test('GET /api/health/live should return status ok', async ({ request }) => {
+      const response = await request.get('/api/health/live');
+
+      expect(response.ok()).toBeTruthy();
+      const data = await response.json();
+      expect(data.status).toBe('ok');
+    });
+
+    test('GET /api/health/ready should report every readiness check', async ({ request }) => {
+      const response = await request.get('/api/health/ready');
+
+      expect(response.ok()).toBeTruthy();
+      const data = await response.json();
+      expect(data.status).toBe('ok');
+
+      const names = data.checks.map(check => check.name);
+      expect(names).toEqual(['data_dir_writable', 'applications_file', 'templates', 'disk_space']);
+      for (const check of data.checks) {
+        expect(check.status).toBe('ok');
+      }
+    });
+
     test('GET /api/applications should return all applications with pagination', async ({ request }) => {
       const response = await request.get('/api/applications');
 
@@ -153,6 +175,38 @@ test.describe('API Endpoints', () => {
       expect(responseData.success).toBeFalsy();
       expect(responseData.message).toContain('required');
     });
+
+    test('POST /api/applications should reject unknown statuses', async ({ request }) => {
+      const response = await request.post('/api/applications', {
+        data: { company: 'Status Co', position: 'Engineer', status: 'Hired' }
+      });
+
+      expect(response.status()).toBe(400);
+      const responseData = await response.json();
+      expect(responseData.message).toContain('Invalid status');
+    });
+
+    test('POST /api/applications should replay retries with the same Idempotency-Key', async ({ request }) => {
+      const headers = { 'Idempotency-Key': `create-${Date.now()}-${Math.random()}` };
+      const data = { company: 'Idempotent Corp', position: 'Retry Engineer' };
+
+      const first = await request.post('/api/applications', { data, headers });
+      expect(first.status()).toBe(201);
+      const created = (await first.json()).data;
+
+      const retry = await request.post('/api/applications', { data, headers });
+      expect(retry.status()).toBe(201);
+      expect(retry.headers()['idempotent-replayed']).toBe('true');
+      expect((await retry.json()).data.id).toBe(created.id);
+
+      const conflict = await request.post('/api/applications', {
+        data: { ...data, position: 'Different Engineer' },
+        headers
+      });
+      expect(conflict.status()).toBe(409);
+
+      await request.delete(`/api/applications/${created.id}`);
+    });
   });
 
   test.describe('PUT Endpoints', () => {
@@ -222,4 +276,679 @@ test.describe('API Endpoints', () => {
       expect(getResponse.status()).toBe(404);
     });
   });
+
+  test.describe('API Tokens', () => {
+    test('should enforce token scopes and revocation', async ({ request, playwright }) => {
+      // Create a read-only token using the logged-in session
+      const createResponse = await request.post('/api/tokens', {
+        data: { name: 'e2e read-only', scopes: ['applications:read'], expiresInDays: 1 }
+      });
+      expect(createResponse.status()).toBe(201);
+      const created = (await createResponse.json()).data;
+      expect(created.token).toMatch(/^at_/);
+      expect(created.tokenHash).toBeUndefined();
+
+      // Use the token without the session cookie
+      const tokenContext = await playwright.request.newContext({
+        baseURL: 'http://localhost:8080',
+        storageState: { cookies: [], origins: [] },
+        extraHTTPHeaders: { Authorization: `Bearer ${created.token}` }
+      });
+
+      const readResponse = await tokenContext.get('/api/applications');
+      expect(readResponse.ok()).toBeTruthy();
+
+      const writeResponse = await tokenContext.post('/api/applications', {
+        data: { company: 'Scoped', position: 'Token' }
+      });
+      expect(writeResponse.status()).toBe(403);
+
+      // Revoke the token and check it no longer works
+      const revokeResponse = await request.delete(`/api/tokens/${created.id}`);
+      expect(revokeResponse.ok()).toBeTruthy();
+
+      const revokedResponse = await tokenContext.get('/api/applications');
+      expect(revokedResponse.status()).toBe(401);
+
+      await tokenContext.dispose();
+    });
+  });
+});
+
+test.describe('Security', () => {
+  test('should reject state-changing requests without a CSRF token', async ({ request }) => {
+    const response = await request.post('/api/applications', {
+      headers: { 'X-CSRF-Token': 'forged' },
+      data: { company: 'Forged Corp', position: 'Attacker' }
+    });
+    expect(response.status()).toBe(403);
+  });
+
+  test('should send security headers', async ({ request }) => {
+    const response = await request.get('/');
+    const headers = response.headers();
+    expect(headers['content-security-policy']).toContain("frame-ancestors 'none'");
+    expect(headers['x-frame-options']).toBe('DENY');
+    expect(headers['referrer-policy']).toBeTruthy();
+  });
+});
+
+test.describe('Request Limits', () => {
+  test('should reject applications with too many tags', async ({ request }) => {
+    const tags = Array.from({ length: 21 }, (_, i) => `tag-${i}`);
+    const response = await request.post('/api/applications', {
+      data: { company: 'Tagged Corp', position: 'Tagger', tags }
+    });
+    expect(response.status()).toBe(400);
+    expect((await response.json()).message).toContain('at most 20 tags');
+  });
+
+  test('should reject oversized request bodies', async ({ request }) => {
+    const response = await request.post('/api/applications', {
+      data: { company: 'Big Corp', position: 'Writer', description: 'x'.repeat(2 << 20) }
+    });
+    expect(response.status()).toBe(413);
+  });
+});
+
+test.describe('Duplicate Applications', () => {
+  test('should warn about likely duplicates and merge them', async ({ request }) => {
+    const company = `Dupe Corp ${Date.now()}`;
+    const first = await request.post('/api/applications', {
+      data: { company: `${company}, Inc.`, position: 'Sr. Backend Engineer', tags: ['go'], description: 'First posting' }
+    });
+    expect(first.status()).toBe(201);
+    const original = (await first.json()).data;
+
+    const second = await request.post('/api/applications', {
+      data: { company: company.toUpperCase(), position: 'Senior Backend Engineer', tags: ['remote'], description: 'Second posting' }
+    });
+    expect(second.status()).toBe(201);
+    const secondData = await second.json();
+    expect(secondData.warnings[0].code).toBe('possible_duplicate');
+    expect(secondData.warnings[0].applicationIds).toContain(original.id);
+
+    const duplicates = await request.get(`/api/applications/${original.id}/duplicates`);
+    expect((await duplicates.json()).data.map(app => app.id)).toContain(secondData.data.id);
+
+    const merge = await request.post(`/api/applications/${original.id}/merge`, {
+      data: { duplicateId: secondData.data.id }
+    });
+    expect(merge.ok()).toBeTruthy();
+    const merged = (await merge.json()).data;
+    expect(merged.tags).toEqual(['go', 'remote']);
+    expect(merged.description).toContain('Second posting');
+
+    const removed = await request.get(`/api/applications/${secondData.data.id}`);
+    expect(removed.status()).toBe(404);
+
+    await request.delete(`/api/applications/${original.id}`);
+  });
+
+  test('should reject merges that exceed the tag limit', async ({ request }) => {
+    const ids = [];
+    for (const prefix of ['a', 'b']) {
+      const tags = Array.from({ length: 11 }, (_, i) => `${prefix}${i}`);
+      const response = await request.post('/api/applications', {
+        data: { company: `Tag Merge ${prefix} ${Date.now()}`, position: 'Engineer', tags }
+      });
+      expect(response.status()).toBe(201);
+      ids.push((await response.json()).data.id);
+    }
+
+    const merge = await request.post(`/api/applications/${ids[0]}/merge`, {
+      data: { duplicateId: ids[1] }
+    });
+    expect(merge.status()).toBe(400);
+    expect((await merge.json()).message).toContain('tags');
+
+    for (const id of ids) {
+      const kept = await request.get(`/api/applications/${id}`);
+      expect(kept.ok()).toBeTruthy();
+      await request.delete(`/api/applications/${id}`);
+    }
+  });
+});
+
+test.describe('Bulk Operations', () => {
+  test('should apply operations atomically with per-item results', async ({ request }) => {
+    const ids = [];
+    for (const company of ['Bulk One', 'Bulk Two']) {
+      const response = await request.post('/api/applications', {
+        data: { company: `${company} ${Date.now()}`, position: 'Bulk Tester' }
+      });
+      ids.push((await response.json()).data.id);
+    }
+
+    const failed = await request.post('/api/applications/bulk', {
+      data: {
+        operations: [
+          { id: ids[0], action: 'set_status', status: 'rejected' },
+          { id: 'does-not-exist', action: 'delete' }
+        ]
+      }
+    });
+    expect(failed.status()).toBe(400);
+    const failedData = await failed.json();
+    expect(failedData.data[1].error).toContain('not found');
+    const unchanged = await request.get(`/api/applications/${ids[0]}`);
+    expect((await unchanged.json()).data.status).toBe('applied');
+
+    const response = await request.post('/api/applications/bulk', {
+      data: {
+        operations: [
+          { id: ids[0], action: 'set_status', status: 'rejected' },
+          { id: ids[0], action: 'add_tag', tag: 'bulk' },
+          { id: ids[1], action: 'delete' }
+        ]
+      }
+    });
+    expect(response.ok()).toBeTruthy();
+    const data = await response.json();
+    expect(data.data.every(result => result.success)).toBeTruthy();
+    expect(data.data[1].application.status).toBe('rejected');
+    expect(data.data[1].application.tags).toContain('bulk');
+
+    const deleted = await request.get(`/api/applications/${ids[1]}`);
+    expect(deleted.status()).toBe(404);
+
+    await request.delete(`/api/applications/${ids[0]}`);
+  });
+});
+
+test.describe('Tags', () => {
+  test('should normalize, rename, merge and color tags', async ({ request }) => {
+    const suffix = Date.now();
+    const created = await request.post('/api/applications', {
+      data: { company: 'Tag Corp', position: 'Tag Tester', tags: [`Remote-${suffix}`, ` remote-${suffix} `, `Go-${suffix}`] }
+    });
+    const application = (await created.json()).data;
+    expect(application.tags).toEqual([`remote-${suffix}`, `go-${suffix}`]);
+
+    const renamed = await request.post('/api/tags/rename', {
+      data: { from: `remote-${suffix}`, to: `anywhere-${suffix}` }
+    });
+    expect(renamed.ok()).toBeTruthy();
+
+    const conflict = await request.post('/api/tags/rename', {
+      data: { from: `anywhere-${suffix}`, to: `go-${suffix}` }
+    });
+    expect(conflict.status()).toBe(409);
+
+    const color = await request.put('/api/tags/color', {
+      data: { tag: `go-${suffix}`, color: 'green' }
+    });
+    expect(color.ok()).toBeTruthy();
+
+    const merged = await request.post('/api/tags/merge', {
+      data: { tags: [`anywhere-${suffix}`, `go-${suffix}`], into: `merged-${suffix}` }
+    });
+    expect(merged.ok()).toBeTruthy();
+
+    const tags = (await (await request.get('/api/tags')).json()).data;
+    const tag = tags.find(t => t.name === `merged-${suffix}`);
+    expect(tag.count).toBe(1);
+    expect(tag.color).toBe('green');
+
+    const updated = await request.get(`/api/applications/${application.id}`);
+    expect((await updated.json()).data.tags).toEqual([`merged-${suffix}`]);
+
+    await request.delete(`/api/applications/${application.id}`);
+  });
+});
+
+test.describe('Auto-Tag Rules', () => {
+  test('should tag new applications and preview tags for existing ones', async ({ request }) => {
+    const keyword = `zq${Date.now()}`;
+    const existing = await request.post('/api/applications', {
+      data: { company: 'Auto Corp', position: 'Existing Role', description: `Uses ${keyword} daily` }
+    });
+    const existingId = (await existing.json()).data.id;
+
+    const rule = await request.post('/api/autotag/rules', {
+      data: { type: 'keyword', pattern: keyword, tag: `${keyword}-tag` }
+    });
+    expect(rule.status()).toBe(201);
+    const ruleId = (await rule.json()).data.id;
+
+    const invalid = await request.post('/api/autotag/rules', {
+      data: { type: 'regex', pattern: '([', tag: 'broken' }
+    });
+    expect(invalid.status()).toBe(400);
+
+    const created = await request.post('/api/applications', {
+      data: { company: 'Auto Corp', position: `${keyword.toUpperCase()} Engineer` }
+    });
+    const createdData = (await created.json()).data;
+    expect(createdData.tags).toContain(`${keyword}-tag`);
+
+    const preview = await request.get('/api/autotag/preview');
+    const change = (await preview.json()).data.find(c => c.applicationId === existingId);
+    expect(change.tags).toEqual([`${keyword}-tag`]);
+
+    const unchanged = await request.get(`/api/applications/${existingId}`);
+    expect((await unchanged.json()).data.tags || []).not.toContain(`${keyword}-tag`);
+
+    const applied = await request.post('/api/autotag/apply');
+    expect(applied.ok()).toBeTruthy();
+    const tagged = await request.get(`/api/applications/${existingId}`);
+    expect((await tagged.json()).data.tags).toContain(`${keyword}-tag`);
+
+    await request.delete(`/api/autotag/rules/${ruleId}`);
+    await request.delete(`/api/applications/${existingId}`);
+    await request.delete(`/api/applications/${createdData.id}`);
+  });
+});
+
+test.describe('Notes', () => {
+  test('should create, edit, search and delete notes', async ({ request }) => {
+    const created = await request.post('/api/applications', {
+      data: { company: 'Notes Corp', position: 'Engineer' }
+    });
+    const id = (await created.json()).data.id;
+
+    const invalid = await request.post(`/api/applications/${id}/notes`, { data: { body: '  ' } });
+    expect(invalid.status()).toBe(400);
+
+    const keyword = `xq${Date.now()}`;
+    const note = await request.post(`/api/applications/${id}/notes`, {
+      data: { body: `Recruiter mentioned ${keyword}` }
+    });
+    expect(note.status()).toBe(201);
+    const noteData = (await note.json()).data;
+    expect(noteData.authorName).toBeTruthy();
+    expect(noteData.editedAt).toBeUndefined();
+
+    const search = await request.get(`/api/applications/search?q=${keyword}`);
+    expect((await search.json()).data.map(app => app.id)).toEqual([id]);
+
+    const updated = await request.put(`/api/applications/${id}/notes/${noteData.id}`, {
+      data: { body: 'Second round scheduled' }
+    });
+    expect(updated.ok()).toBeTruthy();
+    const updatedData = (await updated.json()).data;
+    expect(updatedData.body).toBe('Second round scheduled');
+    expect(updatedData.editedAt).toBeTruthy();
+
+    const list = await request.get(`/api/applications/${id}/notes`);
+    expect((await list.json()).data).toHaveLength(1);
+
+    const deleted = await request.delete(`/api/applications/${id}/notes/${noteData.id}`);
+    expect(deleted.ok()).toBeTruthy();
+    const missing = await request.get(`/api/applications/${id}/notes/${noteData.id}`);
+    expect(missing.status()).toBe(404);
+
+    await request.delete(`/api/applications/${id}`);
+  });
+});
+
+test.describe('Interviews', () => {
+  test('should schedule interviews and move the application to in progress', async ({ request }) => {
+    const created = await request.post('/api/applications', {
+      data: { company: 'Interview Corp', position: 'Engineer' }
+    });
+    const id = (await created.json()).data.id;
+
+    const invalid = await request.post(`/api/applications/${id}/interviews`, {
+      data: { round: 'technical', startsAt: '2030-01-01T10:00:00Z', timeZone: 'Nowhere/Special' }
+    });
+    expect(invalid.status()).toBe(400);
+
+    const startsAt = new Date(Date.now() + 2 * 24 * 60 * 60 * 1000);
+    startsAt.setMilliseconds(0);
+    const scheduled = await request.post(`/api/applications/${id}/interviews`, {
+      data: {
+        round: 'technical',
+        startsAt: startsAt.toISOString(),
+        timeZone: 'America/New_York',
+        interviewers: ['Ana Lee', 'Sam Park']
+      }
+    });
+    expect(scheduled.status()).toBe(201);
+    const interview = (await scheduled.json()).data;
+    expect(interview.outcome).toBe('pending');
+    expect(new Date(interview.endsAt) - new Date(interview.startsAt)).toBe(60 * 60 * 1000);
+
+    const application = await request.get(`/api/applications/${id}`);
+    expect((await application.json()).data.status).toBe('in_progress');
+
+    const upcoming = await request.get('/api/interviews/upcoming?days=7');
+    const entry = (await upcoming.json()).data.find(i => i.id === interview.id);
+    expect(entry.company).toBe('Interview Corp');
+
+    const updated = await request.put(`/api/applications/${id}/interviews/${interview.id}`, {
+      data: { ...interview, outcome: 'passed' }
+    });
+    expect(updated.ok()).toBeTruthy();
+    expect((await updated.json()).data.outcome).toBe('passed');
+
+    const afterOutcome = await request.get('/api/interviews/upcoming?days=7');
+    expect((await afterOutcome.json()).data.some(i => i.id === interview.id)).toBeFalsy();
+
+    await request.delete(`/api/applications/${id}`);
+    const gone = await request.get(`/api/applications/${id}/interviews/${interview.id}`);
+    expect(gone.status()).toBe(404);
+  });
+
+  test('should count the first interview as activity', async ({ request }) => {
+    const appliedAt = new Date(Date.now() - 30 * 24 * 60 * 60 * 1000).toISOString();
+    const created = await request.post('/api/applications', {
+      data: { company: 'Backdated Corp', position: 'Engineer', appliedAt }
+    });
+    const app = (await created.json()).data;
+    expect(new Date(app.lastActivityAt).getTime()).toBe(new Date(appliedAt).getTime());
+
+    const before = Date.now();
+    const startsAt = new Date(Date.now() + 2 * 24 * 60 * 60 * 1000);
+    const scheduled = await request.post(`/api/applications/${app.id}/interviews`, {
+      data: { round: 'phone_screen', startsAt: startsAt.toISOString(), timeZone: 'UTC' }
+    });
+    expect(scheduled.status()).toBe(201);
+
+    const application = (await (await request.get(`/api/applications/${app.id}`)).json()).data;
+    expect(application.status).toBe('in_progress');
+    expect(new Date(application.lastActivityAt).getTime()).toBeGreaterThanOrEqual(before - 1000);
+    const stale = await request.get('/api/stale');
+    expect((await stale.json()).data.some(a => a.id === app.id)).toBeFalsy();
+
+    await request.delete(`/api/applications/${app.id}`);
+  });
+});
+
+test.describe('Contacts', () => {
+  test('should link contacts and applications both ways', async ({ request }) => {
+    const suffix = Date.now();
+    const first = await request.post('/api/applications', { data: { company: `Contact Corp ${suffix}`, position: 'Engineer' } });
+    const second = await request.post('/api/applications', { data: { company: `Other Corp ${suffix}`, position: 'Engineer' } });
+    const firstId = (await first.json()).data.id;
+    const secondId = (await second.json()).data.id;
+
+    const invalid = await request.post('/api/contacts', { data: { name: 'No Email', email: 'not-an-email' } });
+    expect(invalid.status()).toBe(400);
+
+    const created = await request.post('/api/contacts', {
+      data: { name: `Jane ${suffix}`, role: 'recruiter', company: 'Contact Corp', email: 'jane@example.com' }
+    });
+    expect(created.status()).toBe(201);
+    const contact = (await created.json()).data;
+    expect(contact.applicationIds).toEqual([]);
+
+    for (const id of [firstId, secondId]) {
+      const linked = await request.post(`/api/applications/${id}/contacts`, { data: { contactId: contact.id } });
+      expect(linked.ok()).toBeTruthy();
+    }
+
+    const applications = await request.get(`/api/contacts/${contact.id}/applications`);
+    expect((await applications.json()).data.map(app => app.id).sort()).toEqual([firstId, secondId].sort());
+
+    const contacts = await request.get(`/api/applications/${firstId}/contacts`);
+    expect((await contacts.json()).data.map(c => c.id)).toEqual([contact.id]);
+
+    const search = await request.get(`/api/contacts?q=jane ${suffix}`);
+    expect((await search.json()).data.map(c => c.id)).toEqual([contact.id]);
+
+    const unlinked = await request.delete(`/api/applications/${secondId}/contacts/${contact.id}`);
+    expect(unlinked.ok()).toBeTruthy();
+    await request.delete(`/api/applications/${firstId}`);
+    const remaining = await request.get(`/api/contacts/${contact.id}`);
+    expect((await remaining.json()).data.applicationIds).toEqual([]);
+
+    await request.delete(`/api/contacts/${contact.id}`);
+    await request.delete(`/api/applications/${secondId}`);
+  });
+});
+
+test.describe('Companies', () => {
+  test('should resolve company name variants to one company and merge companies', async ({ request }) => {
+    const suffix = Date.now();
+    const first = await request.post('/api/applications', { data: { company: `Globex ${suffix} LLC`, position: 'Engineer' } });
+    const second = await request.post('/api/applications', { data: { company: `globex ${suffix}`, position: 'Manager', status: 'rejected' } });
+    const third = await request.post('/api/applications', { data: { company: `Initech ${suffix}`, position: 'Analyst' } });
+    const firstApp = (await first.json()).data;
+    const secondApp = (await second.json()).data;
+    const thirdApp = (await third.json()).data;
+    expect(firstApp.companyId).toBeTruthy();
+    expect(secondApp.companyId).toBe(firstApp.companyId);
+    expect(thirdApp.companyId).not.toBe(firstApp.companyId);
+
+    const company = await request.get(`/api/companies/${firstApp.companyId}`);
+    const history = (await company.json()).data.history;
+    expect(history.applications).toBe(2);
+    expect(history.byStatus).toEqual({ applied: 1, rejected: 1 });
+
+    const inUse = await request.delete(`/api/companies/${thirdApp.companyId}`);
+    expect(inUse.status()).toBe(409);
+
+    const merged = await request.post(`/api/companies/${firstApp.companyId}/merge`, { data: { companyId: thirdApp.companyId } });
+    expect(merged.ok()).toBeTruthy();
+    expect((await merged.json()).data.company.aliases).toEqual([`Initech ${suffix}`]);
+
+    const applications = await request.get(`/api/companies/${firstApp.companyId}/applications`);
+    expect((await applications.json()).data).toHaveLength(3);
+
+    const fourth = await request.post('/api/applications', { data: { company: `Initech ${suffix} Inc.`, position: 'Designer' } });
+    const fourthApp = (await fourth.json()).data;
+    expect(fourthApp.companyId).toBe(firstApp.companyId);
+
+    for (const app of [firstApp, secondApp, thirdApp, fourthApp]) {
+      await request.delete(`/api/applications/${app.id}`);
+    }
+    const deleted = await request.delete(`/api/companies/${firstApp.companyId}`);
+    expect(deleted.ok()).toBeTruthy();
+  });
+});
+
+test.describe('Reminders', () => {
+  test('should fire due reminders into notifications and snooze them', async ({ request }) => {
+    const created = await request.post('/api/applications', { data: { company: 'Reminder Co', position: 'Engineer' } });
+    const app = (await created.json()).data;
+
+    const rules = await request.get('/api/reminders/rules');
+    const defaults = (await rules.json()).data;
+    expect(defaults.some(rule => rule.status === 'applied' && rule.days === 7)).toBeTruthy();
+
+    const dueAt = new Date(Date.now() - 60 * 1000).toISOString();
+    const reminder = await request.post(`/api/applications/${app.id}/reminders`, { data: { message: 'Follow up', dueAt } });
+    expect(reminder.status()).toBe(201);
+    const reminderId = (await reminder.json()).data.id;
+
+    await expect.poll(async () => {
+      const notifications = await request.get('/api/notifications');
+      return (await notifications.json()).data.notifications.some(n => n.reminderId === reminderId);
+    }, { timeout: 90000 }).toBeTruthy();
+
+    const snoozed = await request.post(`/api/applications/${app.id}/reminders/${reminderId}/snooze`, { data: { hours: 24 } });
+    expect(snoozed.ok()).toBeTruthy();
+    const snoozedReminder = (await snoozed.json()).data;
+    expect(snoozedReminder.firedAt).toBeUndefined();
+    expect(new Date(snoozedReminder.dueAt).getTime()).toBeGreaterThan(Date.now());
+
+    const notifications = await request.get('/api/notifications');
+    const notification = (await notifications.json()).data.notifications.find(n => n.reminderId === reminderId);
+    expect(notification.readAt).toBeTruthy();
+
+    const done = await request.put(`/api/applications/${app.id}/reminders/${reminderId}`, { data: { done: true } });
+    expect((await done.json()).data.done).toBe(true);
+
+    await request.delete(`/api/applications/${app.id}`);
+    const gone = await request.get(`/api/applications/${app.id}/reminders`);
+    expect(gone.status()).toBe(404);
+  });
+});
+
+test.describe('Stale Applications', () => {
+  test('should validate stale settings and accept the ghosted status', async ({ request }) => {
+    const defaults = await request.get('/api/stale/settings');
+    expect((await defaults.json()).data.days).toEqual({ applied: 21, in_progress: 14 });
+
+    const invalid = await request.put('/api/stale/settings', { data: { days: { applied: 30 }, ghostAfterDays: 10 } });
+    expect(invalid.status()).toBe(400);
+    const terminal = await request.put('/api/stale/settings', { data: { days: { rejected: 5 } } });
+    expect(terminal.status()).toBe(400);
+
+    const updated = await request.put('/api/stale/settings', { data: { days: { applied: 30, in_progress: 14 }, ghostAfterDays: 60 } });
+    expect(updated.ok()).toBeTruthy();
+    expect((await updated.json()).data.ghostAfterDays).toBe(60);
+
+    const created = await request.post('/api/applications', { data: { company: 'Quiet Co', position: 'Engineer' } });
+    const app = (await created.json()).data;
+    const stale = await request.get('/api/stale');
+    expect((await stale.json()).data.some(a => a.id === app.id)).toBeFalsy();
+
+    const ghosted = await request.put(`/api/applications/${app.id}/status`, { data: { status: 'ghosted' } });
+    expect((await ghosted.json()).data.status).toBe('ghosted');
+
+    await request.delete(`/api/applications/${app.id}`);
+    await request.put('/api/stale/settings', { data: { days: { applied: 21, in_progress: 14 } } });
+  });
+});
+
+test.describe('Compensation', () => {
+  test('should filter by yearly base pay and compare offers', async ({ request }) => {
+    const posted = await request.post('/api/applications', {
+      data: { company: 'Pay Co', position: 'Engineer', compensation: { baseMin: 10000, baseMax: 12000, currency: 'usd', period: 'month' } }
+    });
+    const app = (await posted.json()).data;
+    expect(app.compensation.currency).toBe('USD');
+
+    const invalid = await request.post('/api/applications', {
+      data: { company: 'Pay Co', position: 'Engineer', compensation: { baseMin: 200, baseMax: 100, currency: 'USD' } }
+    });
+    expect(invalid.status()).toBe(400);
+
+    const hourly = await request.post('/api/applications', {
+      data: { company: 'Pay Co', position: 'Contractor', compensation: { baseMin: 60, currency: 'USD', period: 'hour', bonus: 500000 } }
+    });
+    expect(hourly.status()).toBe(201);
+    expect((await hourly.json()).data.compensation.bonus).toBe(500000);
+
+    const inRange = await request.get('/api/applications/search?salaryMin=130000&currency=USD');
+    expect((await inRange.json()).data.some(a => a.id === app.id)).toBeTruthy();
+    const outOfRange = await request.get('/api/applications/search?salaryMin=150000');
+    expect((await outOfRange.json()).data.some(a => a.id === app.id)).toBeFalsy();
+
+    await request.put(`/api/applications/${app.id}`, {
+      data: { status: 'in_progress', offer: { baseMin: 140000, currency: 'USD', bonus: 10000, equity: 5000 } }
+    });
+    const offers = await request.get('/api/offers');
+    const offer = (await offers.json()).data.find(o => o.applicationId === app.id);
+    expect(offer.annualTotal).toBe(155000);
+
+    await request.delete(`/api/applications/${app.id}`);
+  });
+});
+
+test.describe('Location and Work Mode', () => {
+  test('should filter by work mode, location and UTC offset', async ({ request }) => {
+    const posted = await request.post('/api/applications', {
+      data: {
+        company: 'Place Co', position: 'Engineer',
+        location: { city: 'Berlin', country: 'Germany' }, workMode: 'Hybrid',
+        timeZone: { minOffset: -5, maxOffset: 1 }
+      }
+    });
+    const app = (await posted.json()).data;
+    expect(app.workMode).toBe('hybrid');
+
+    const invalid = await request.post('/api/applications', {
+      data: { company: 'Place Co', position: 'Engineer', workMode: 'sometimes' }
+    });
+    expect(invalid.status()).toBe(400);
+
+    const matching = await request.get('/api/applications/search?workMode=hybrid&location=germany&utcOffset=-4');
+    expect((await matching.json()).data.some(a => a.id === app.id)).toBeTruthy();
+    const wrongMode = await request.get('/api/applications/search?workMode=remote');
+    expect((await wrongMode.json()).data.some(a => a.id === app.id)).toBeFalsy();
+    const wrongOffset = await request.get('/api/applications/search?utcOffset=9');
+    expect((await wrongOffset.json()).data.some(a => a.id === app.id)).toBeFalsy();
+
+    const cleared = await request.put(`/api/applications/${app.id}`, { data: { timeZone: {} } });
+    const updated = (await cleared.json()).data;
+    expect(updated.timeZone).toBeUndefined();
+    expect(updated.location.city).toBe('Berlin');
+
+    await request.delete(`/api/applications/${app.id}`);
+  });
+});
+
+test.describe('Application Dates', () => {
+  test('should backdate applications and order them by applied date', async ({ request }) => {
+    const late = await request.post('/api/applications', {
+      data: { company: 'Dates Co', position: 'Engineer', appliedAt: '2020-01-15', postedAt: '2020-01-01', closingAt: '2020-02-01' }
+    });
+    expect(late.status()).toBe(201);
+    const app = (await late.json()).data;
+    expect(app.appliedAt.startsWith('2020-01-15')).toBeTruthy();
+    expect(app.statusChangedAt.startsWith('2020-01-15')).toBeTruthy();
+
+    const future = await request.post('/api/applications', {
+      data: { company: 'Dates Co', position: 'Engineer', appliedAt: '2999-01-01' }
+    });
+    expect(future.status()).toBe(400);
+
+    const recent = await request.post('/api/applications', { data: { company: 'Dates Co', position: 'Designer' } });
+    const recentApp = (await recent.json()).data;
+    const search = await request.get('/api/applications/search?q=Dates%20Co');
+    const ids = (await search.json()).data.map(a => a.id);
+    expect(ids.indexOf(recentApp.id)).toBeLessThan(ids.indexOf(app.id));
+
+    const cleared = await request.put(`/api/applications/${app.id}`, { data: { closingAt: '' } });
+    const updated = (await cleared.json()).data;
+    expect(updated.closingAt).toBeUndefined();
+    expect(updated.postedAt.startsWith('2020-01-01')).toBeTruthy();
+
+    await request.delete(`/api/applications/${app.id}`);
+    await request.delete(`/api/applications/${recentApp.id}`);
+  });
+});
+
+test.describe('Custom Fields', () => {
+  test('should validate custom field values and search by them', async ({ request }) => {
+    const created = await request.post('/api/fields', { data: { name: 'Visa sponsorship', type: 'bool' } });
+    expect(created.status()).toBe(201);
+    const field = (await created.json()).data;
+    expect(field.key).toBe('visa_sponsorship');
+    const team = (await (await request.post('/api/fields', {
+      data: { name: 'Team', type: 'select', options: ['Platform', 'Payments'] }
+    })).json()).data;
+
+    const duplicate = await request.post('/api/fields', { data: { name: 'visa sponsorship!', type: 'text' } });
+    expect(duplicate.status()).toBe(409);
+
+    const posted = await request.post('/api/applications', {
+      data: { company: 'Fields Co', position: 'Engineer', fields: { visa_sponsorship: true, team: 'payments' } }
+    });
+    const app = (await posted.json()).data;
+    expect(app.fields).toEqual({ visa_sponsorship: 'true', team: 'Payments' });
+
+    const invalid = await request.post('/api/applications', {
+      data: { company: 'Fields Co', position: 'Engineer', fields: { team: 'Marketing' } }
+    });
+    expect(invalid.status()).toBe(400);
+    const unknown = await request.post('/api/applications', {
+      data: { company: 'Fields Co', position: 'Engineer', fields: { salary_band: 'L5' } }
+    });
+    expect(unknown.status()).toBe(400);
+
+    const headcount = (await (await request.post('/api/fields', { data: { name: 'Headcount', type: 'number' } })).json()).data;
+    for (const value of ['NaN', 'Inf', '-Infinity']) {
+      const notNumber = await request.post('/api/applications', {
+        data: { company: 'Fields Co', position: 'Engineer', fields: { headcount: value } }
+      });
+      expect(notNumber.status()).toBe(400);
+    }
+    await request.delete(`/api/fields/${headcount.id}`);
+
+    const matching = await request.get('/api/applications/search?field.visa_sponsorship=yes&field.team=Payments');
+    expect((await matching.json()).data.some(a => a.id === app.id)).toBeTruthy();
+    const notMatching = await request.get('/api/applications/search?field.visa_sponsorship=no');
+    expect((await notMatching.json()).data.some(a => a.id === app.id)).toBeFalsy();
+
+    await request.delete(`/api/fields/${team.id}`);
+    const updated = (await (await request.get(`/api/applications/${app.id}`)).json()).data;
+    expect(updated.fields).toEqual({ visa_sponsorship: 'true' });
+
+    await request.delete(`/api/fields/${field.id}`);
+    await request.delete(`/api/applications/${app.id}`);
+  });
 });
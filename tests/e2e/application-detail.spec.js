@@ -157,4 +157,24 @@ test.describe('Application Detail Page', () => {
     // Check that we're back on the applications page
     await expect(page).toHaveTitle(/Applications/);
   });
+
+  test('should add and edit notes inline', async ({ page }) => {
+    // Navigate to the application detail page
+    await page.goto(`/applications/${applicationId}`);
+
+    // Add a note
+    const notes = page.locator('#notes');
+    await notes.locator('textarea[name="body"]').fill('Recruiter call on Monday');
+    await notes.locator('button', { hasText: 'Add Note' }).click();
+    const note = notes.locator('li.note');
+    await expect(note).toHaveCount(1);
+    await expect(note).toContainText('Recruiter call on Monday');
+
+    // Edit it in place
+    await note.locator('button', { hasText: 'Edit' }).click();
+    await note.locator('textarea[name="body"]').fill('Recruiter call moved to Tuesday');
+    await note.locator('button', { hasText: 'Save' }).click();
+    await expect(notes.locator('li.note')).toContainText('Recruiter call moved to Tuesday');
+    await expect(notes.locator('li.note')).toContainText('(edited)');
+  });
 });
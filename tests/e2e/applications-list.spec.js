@@ -129,4 +129,35 @@ test.describe('Applications List Page', () => {
       expect(parseInt(currentPage)).toBeGreaterThan(1);
     }
   });
+
+  test('should tag selected applications with the bulk action bar', async ({ page, request }) => {
+    const tag = `bulk-${Date.now()}`;
+    const created = await request.post('/api/applications', {
+      data: { company: 'Bulk UI Corp', position: 'Bulk UI Tester' }
+    });
+    const id = (await created.json()).data.id;
+
+    await page.goto('/applications');
+    await page.fill('input[name="q"]', 'Bulk UI Corp');
+    await page.click('button[type="submit"]');
+    await page.waitForResponse(response => 
+      response.url().includes('/htmx/applications/search') && 
+      response.status() === 200
+    );
+
+    // Select the application and add a tag to it
+    await page.check(`#select-${id}`);
+    await page.selectOption('#bulk-action', 'add_tag');
+    await page.fill('#bulk-tag', tag);
+    page.once('dialog', dialog => dialog.accept());
+    await Promise.all([
+      page.waitForResponse(response => response.url().includes('/api/applications/bulk')),
+      page.click('#bulk-form button[type="submit"]')
+    ]);
+
+    const response = await request.get(`/api/applications/${id}`);
+    expect((await response.json()).data.tags).toContain(tag);
+
+    await request.delete(`/api/applications/${id}`);
+  });
 });
@@ -0,0 +1,192 @@
+const { test, expect } = require('@playwright/test');
+const { TEST_USER, registerUser } = require('../global-setup');
+
+test.describe('Authentication', () => {
+  test.describe('anonymous visitors', () => {
+    // Start without the shared session cookie
+    test.use({ storageState: { cookies: [], origins: [] } });
+
+    test('should redirect protected pages to the login page', async ({ page }) => {
+      await page.goto('/applications');
+
+      await expect(page).toHaveURL(/\/login\?next=%2Fapplications/);
+      await expect(page).toHaveTitle(/Log In/);
+    });
+
+    test('should reject API requests without a session', async ({ request }) => {
+      const response = await request.get('/api/applications');
+
+      expect(response.status()).toBe(401);
+      const data = await response.json();
+      expect(data.success).toBeFalsy();
+    });
+
+    test('should keep health checks public', async ({ request }) => {
+      const response = await request.get('/api/health/live');
+
+      expect(response.ok()).toBeTruthy();
+    });
+
+    test('should show an error for invalid credentials', async ({ page }) => {
+      await page.goto('/login');
+      await page.fill('#username', TEST_USER.username);
+      await page.fill('#password', 'definitely-wrong');
+      await page.click('button[type="submit"]');
+
+      await expect(page.locator('text=Invalid username or password')).toBeVisible();
+    });
+
+    test('should log in and return to the requested page', async ({ page }) => {
+      await page.goto('/applications');
+      await page.fill('#username', TEST_USER.username);
+      await page.fill('#password', TEST_USER.password);
+      await page.click('button[type="submit"]');
+
+      await expect(page).toHaveURL('/applications');
+      await expect(page.locator('header')).toContainText(TEST_USER.username);
+    });
+  });
+
+  test('should log out', async ({ browser }) => {
+    // Log in with a separate session so the shared one stays valid
+    const context = await browser.newContext({ storageState: { cookies: [], origins: [] } });
+    const page = await context.newPage();
+    await page.goto('/login');
+    await page.fill('#username', TEST_USER.username);
+    await page.fill('#password', TEST_USER.password);
+    await page.click('button[type="submit"]');
+    await expect(page).toHaveURL('/');
+
+    await page.click('text=Log out');
+
+    await expect(page).toHaveURL('/login');
+    await page.goto('/applications');
+    await expect(page).toHaveURL(/\/login/);
+    await context.close();
+  });
+
+  test('should keep applications private to their owner', async ({ request }) => {
+    const created = await request.post('/api/applications', {
+      data: { company: 'Private Corp', position: 'Secret Role' }
+    });
+    expect(created.ok()).toBeTruthy();
+    const application = (await created.json()).data;
+
+    // Register a second user with its own session
+    const username = `e2e-other-${Date.now()}`;
+    const { context: otherUser, response: registered } =
+      await registerUser('http://localhost:8080', username, 'other-password');
+    expect(registered.status()).toBe(303);
+
+    const getResponse = await otherUser.get(`/api/applications/${application.id}`);
+    expect(getResponse.status()).toBe(404);
+
+    const deleteResponse = await otherUser.delete(`/api/applications/${application.id}`);
+    expect(deleteResponse.status()).toBe(404);
+
+    const searchResponse = await otherUser.get('/api/applications/search?q=Private%20Corp');
+    const searchData = await searchResponse.json();
+    expect(searchData.data || []).toHaveLength(0);
+
+    await otherUser.dispose();
+  });
+});
+
+test.describe('Sharing Links', () => {
+  test('should show a read-only filtered view', async ({ page, request, browser }) => {
+    const tag = `coached-${Date.now()}`;
+    const shared = await request.post('/api/applications', {
+      data: { company: 'Shared Corp', position: 'Coached Role', tags: [tag] }
+    });
+    expect(shared.ok()).toBeTruthy();
+    const hidden = await request.post('/api/applications', {
+      data: { company: 'Hidden Corp', position: 'Private Role', tags: ['private'] }
+    });
+    const hiddenApplication = (await hidden.json()).data;
+
+    await page.goto('/settings');
+    await page.fill('#share-name', 'Career coach');
+    await page.fill('#share-tags', tag);
+    await page.click('text=Create Link');
+    const link = page.locator('#shares .share-link').last();
+    await expect(link).toBeVisible();
+    const shareURL = await link.getAttribute('href');
+
+    // Visit the link without logging in
+    const context = await browser.newContext({ storageState: { cookies: [], origins: [] } });
+    const visitor = await context.newPage();
+    await visitor.goto(shareURL);
+    await expect(visitor.locator('h1')).toHaveText('Career coach');
+    await expect(visitor.locator('#applications-list')).toContainText('Shared Corp');
+    await expect(visitor.locator('#applications-list')).not.toContainText('Hidden Corp');
+    await expect(visitor.locator('text=Add New Application')).toHaveCount(0);
+    await expect(visitor.locator('#applications-list button:has-text("Delete")')).toHaveCount(0);
+
+    await visitor.click('text=View Details');
+    await expect(visitor.locator('h1')).toHaveText('Shared Corp');
+    await expect(visitor.locator('text=Edit')).toHaveCount(0);
+    await expect(visitor.locator('text=Mark as Accepted')).toHaveCount(0);
+
+    const hiddenResponse = await visitor.goto(`${shareURL}/applications/${hiddenApplication.id}`);
+    expect(hiddenResponse.status()).toBe(404);
+
+    // Revoking the link stops it working
+    page.on('dialog', dialog => dialog.accept());
+    await page.locator('#shares button:has-text("Revoke")').last().click();
+    await expect(page.locator('#shares tbody tr').last()).toContainText('Revoked');
+    const revokedResponse = await visitor.goto(shareURL);
+    expect(revokedResponse.status()).toBe(404);
+
+    await context.close();
+  });
+});
+
+test.describe('Workspace Roles', () => {
+  test('should let a commenter comment but not edit', async ({ page, request }) => {
+    const created = await request.post('/api/applications', {
+      data: { company: 'Team Corp', position: 'Shared Role' }
+    });
+    const application = (await created.json()).data;
+
+    // Register a collaborator with its own session
+    const username = `e2e-coach-${Date.now()}`;
+    const { context: collaborator, response: registered } =
+      await registerUser('http://localhost:8080', username, 'coach-password');
+    expect(registered.status()).toBe(303);
+
+    // The owner adds the collaborator as a commenter
+    await page.goto('/settings');
+    await page.fill('#member-username', username);
+    await page.selectOption('#member-role', 'commenter');
+    await page.click('text=Add or Update Member');
+    await expect(page.locator('#members')).toContainText(username);
+
+    const workspaces = await (await collaborator.get('/api/workspaces')).json();
+    const workspace = workspaces.data.find(w => w.role === 'commenter');
+    const headers = { 'X-Workspace-ID': workspace.id };
+
+    const getResponse = await collaborator.get(`/api/applications/${application.id}`, { headers });
+    expect(getResponse.status()).toBe(200);
+
+    const statusResponse = await collaborator.put(`/api/applications/${application.id}/status`, {
+      headers, data: { status: 'rejected' }
+    });
+    expect(statusResponse.status()).toBe(403);
+
+    const deleteResponse = await collaborator.delete(`/api/applications/${application.id}`, { headers });
+    expect(deleteResponse.status()).toBe(403);
+
+    const commentResponse = await collaborator.post(`/api/applications/${application.id}/comments`, {
+      headers, data: { body: 'Follow up next week' }
+    });
+    expect(commentResponse.status()).toBe(201);
+
+    // The owner sees the comment and the audit trail
+    await page.goto(`/applications/${application.id}`);
+    await expect(page.locator('#comments')).toContainText('Follow up next week');
+    await page.goto('/settings');
+    await expect(page.locator('#activity')).toContainText(`${username} commented on Team Corp`);
+
+    await collaborator.dispose();
+  });
+});
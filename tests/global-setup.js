@@ -0,0 +1,82 @@
+const { request } = require('@playwright/test');
+const fs = require('fs');
+const path = require('path');
+
+// Account used by every test; created on first run (the server runs with ALLOW_SIGNUP=true)
+const TEST_USER = {
+  username: 'e2e-tester',
+  password: 'e2e-test-password'
+};
+
+const STORAGE_STATE = path.join(__dirname, '.auth', 'user.json');
+
+/**
+ * Returns the CSRF token a request context received from the server. The token is
+ * set as a cookie on the first page load and must be echoed in the X-CSRF-Token
+ * header of every state-changing request made with a session cookie.
+ */
+async function csrfToken(context) {
+  await context.get('/login');
+  const { cookies } = await context.storageState();
+  return cookies.find(cookie => cookie.name === 'csrf_token').value;
+}
+
+/**
+ * Registers a new user and returns a request context logged in as that user.
+ */
+async function registerUser(baseURL, username, password) {
+  const anonymous = await request.newContext({ baseURL });
+  const token = await csrfToken(anonymous);
+  const context = await request.newContext({
+    baseURL,
+    storageState: await anonymous.storageState(),
+    extraHTTPHeaders: { 'X-CSRF-Token': token }
+  });
+  await anonymous.dispose();
+
+  const response = await context.post('/register', {
+    form: { username, password, confirmPassword: password },
+    maxRedirects: 0
+  });
+  return { context, response };
+}
+
+/**
+ * Logs in as the test user, registering it if needed, and saves the session
+ * cookie so that pages and API requests in the tests are authenticated. The CSRF
+ * token is passed to the tests through the CSRF_TOKEN environment variable.
+ */
+module.exports = async config => {
+  const { baseURL } = config.projects[0].use;
+  const context = await request.newContext({ baseURL });
+  const token = await csrfToken(context);
+
+  let response = await context.post('/login', {
+    form: { username: TEST_USER.username, password: TEST_USER.password, csrf_token: token },
+    maxRedirects: 0
+  });
+
+  if (response.status() !== 303) {
+    response = await context.post('/register', {
+      form: {
+        username: TEST_USER.username,
+        password: TEST_USER.password,
+        confirmPassword: TEST_USER.password,
+        csrf_token: token
+      },
+      maxRedirects: 0
+    });
+    if (response.status() !== 303) {
+      throw new Error(`Failed to register test user: ${response.status()} ${await response.text()}`);
+    }
+  }
+
+  fs.mkdirSync(path.dirname(STORAGE_STATE), { recursive: true });
+  await context.storageState({ path: STORAGE_STATE });
+  await context.dispose();
+  process.env.CSRF_TOKEN = token;
+};
+
+module.exports.TEST_USER = TEST_USER;
+module.exports.STORAGE_STATE = STORAGE_STATE;
+module.exports.registerUser = registerUser;
@@ -6,6 +6,8 @@ const { defineConfig, devices } = require('@playwright/test');
  */
 module.exports = defineConfig({
   testDir: './e2e',
+  /* Log in once and share the session with every test */
+  globalSetup: require.resolve('./global-setup'),
   /* Maximum time one test can run for. */
   timeout: 30 * 1000,
   expect: {
@@ -33,6 +35,12 @@ module.exports = defineConfig({
     
     /* Take screenshot on failure */
     screenshot: 'only-on-failure',
+
+    /* Authenticated session saved by global-setup.js */
+    storageState: './.auth/user.json',
+
+    /* CSRF token of that session, required on state-changing requests */
+    extraHTTPHeaders: process.env.CSRF_TOKEN ? { 'X-CSRF-Token': process.env.CSRF_TOKEN } : {},
   },
 
   /* Configure projects for major browsers */
@@ -53,7 +61,7 @@ module.exports = defineConfig({
 
   /* Run your local dev server before starting the tests */
   webServer: {
-    command: 'cd .. && go run main.go',
+    command: 'cd .. && ALLOW_SIGNUP=true RATE_LIMIT_RPS=0 go run main.go',
     url: 'http://localhost:8080',
     reuseExistingServer: !process.env.CI,
     stdout: 'pipe',
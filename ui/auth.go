@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/ratelimit"
+	"ApplicationTracker/storage"
+)
+
+// loginLimiter throttles login and registration attempts per IP address; set by
+// SetupUIRouter, nil disables it
+var loginLimiter *ratelimit.Limiter
+
+// limitAttempts rejects login and registration submissions from IP addresses that made
+// too many recently, so passwords can't be guessed at speed
+func limitAttempts(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if loginLimiter == nil || r.Method != http.MethodPost {
+			next(w, r)
+			return
+		}
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		if allowed, wait := loginLimiter.Allow(host, time.Now()); !allowed {
+			slog.WarnContext(r.Context(), "throttled login attempts", "path", r.URL.Path)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "Too many attempts, please wait a minute and try again", http.StatusTooManyRequests)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// LoginHandler shows the login form and handles login submissions
+func LoginHandler(w http.ResponseWriter, r *http.Request) {
+	next := safeRedirect(r.FormValue("next"))
+
+	switch r.Method {
+	case http.MethodGet:
+		if auth.UserFromContext(r.Context()) != nil {
+			http.Redirect(w, r, next, http.StatusSeeOther)
+			return
+		}
+		allowSignup, _ := auth.SignupAllowed(r.Context())
+		renderTemplate(w, r, "login", TemplateData{
+			Title:       "Log In",
+			Next:        next,
+			AllowSignup: allowSignup,
+		})
+
+	case http.MethodPost:
+		username := r.FormValue("username")
+		user, err := auth.Login(r.Context(), username, r.FormValue("password"))
+		if err != nil {
+			message := "Invalid username or password"
+			if !errors.Is(err, auth.ErrInvalidCredentials) {
+				slog.ErrorContext(r.Context(), "login failed", "error", err)
+				message = "Login failed, please try again"
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			renderTemplate(w, r, "login", TemplateData{
+				Title:    "Log In",
+				Error:    message,
+				Username: username,
+				Next:     next,
+			})
+			return
+		}
+
+		if err := auth.StartSession(w, r, user); err != nil {
+			slog.ErrorContext(r.Context(), "failed to start session", "error", err)
+			http.Error(w, "Failed to start session", http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, next, http.StatusSeeOther)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// RegisterHandler shows the registration form and creates new accounts
+func RegisterHandler(w http.ResponseWriter, r *http.Request) {
+	allowed, err := auth.SignupAllowed(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to check registration status", http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		http.Error(w, "Registration is disabled", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		renderTemplate(w, r, "register", TemplateData{Title: "Create Account"})
+
+	case http.MethodPost:
+		username := r.FormValue("username")
+		password := r.FormValue("password")
+
+		var message string
+		if password != r.FormValue("confirmPassword") {
+			message = "Passwords do not match"
+		} else if user, err := auth.Register(r.Context(), username, password); err != nil {
+			message = err.Error()
+			if errors.Is(err, storage.ErrUsernameTaken) {
+				message = "That username is already taken"
+			}
+		} else if err := auth.StartSession(w, r, user); err != nil {
+			slog.ErrorContext(r.Context(), "failed to start session", "error", err)
+			http.Error(w, "Failed to start session", http.StatusInternalServerError)
+			return
+		} else {
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		renderTemplate(w, r, "register", TemplateData{
+			Title:    "Create Account",
+			Error:    message,
+			Username: username,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// LogoutHandler ends the current session
+func LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := auth.EndSession(w, r); err != nil {
+		slog.ErrorContext(r.Context(), "failed to end session", "error", err)
+	}
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// requireLogin redirects visitors without a browser session to the login page; API
+// tokens are not accepted so a narrowly scoped token can't reach the settings pages
+func requireLogin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if auth.UserFromContext(r.Context()) != nil && auth.TokenFromContext(r.Context()) == nil {
+			next(w, r)
+			return
+		}
+
+		loginURL := "/login?next=" + url.QueryEscape(r.URL.RequestURI())
+		if r.Header.Get("HX-Request") == "true" {
+			// HTMX follows HX-Redirect with a full page navigation
+			w.Header().Set("HX-Redirect", "/login")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.Redirect(w, r, loginURL, http.StatusSeeOther)
+	}
+}
+
+// safeRedirect only allows local redirect targets, falling back to the home page
+func safeRedirect(target string) string {
+	if target == "" || !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") || strings.Contains(target, "\\") {
+		return "/"
+	}
+	return target
+}
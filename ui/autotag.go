@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"html/template"
+	"net/http"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// HtmxAutoTagHandler lists the auto-tag rules of the current workspace on /htmx/autotag
+// and previews their effect on /htmx/autotag/preview; editors change rules through the API
+func HtmxAutoTagHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data := map[string]interface{}{
+		"CanEdit": auth.HasRole(r.Context(), models.RoleEditor),
+	}
+	file := "templates/htmx/autotag/list.html"
+
+	switch r.URL.Path {
+	case "/htmx/autotag":
+		rules, err := storage.ListAutoTagRules(r.Context(), workspaceID(r))
+		if err != nil {
+			http.Error(w, "Failed to retrieve auto-tag rules", http.StatusInternalServerError)
+			return
+		}
+		data["Rules"] = rules
+
+	case "/htmx/autotag/preview":
+		changes, err := storage.ApplyAutoTagRules(r.Context(), workspaceID(r), maxTags, true)
+		if err != nil {
+			http.Error(w, "Failed to preview auto-tags", http.StatusInternalServerError)
+			return
+		}
+		data["Changes"] = changes
+		file = "templates/htmx/autotag/preview.html"
+
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	tmpl := template.Must(template.ParseFiles(file))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
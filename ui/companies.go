@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// CompaniesHandler handles the companies page
+func CompaniesHandler(w http.ResponseWriter, r *http.Request) {
+	companies, err := storage.ListCompanies(r.Context(), workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve companies", http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, "companies", TemplateData{
+		Title:     "Companies",
+		Companies: companies,
+	})
+}
+
+// CompanyHandler handles the page of a company, listing the applications to it
+func CompanyHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/companies/"), "/")
+	company, err := storage.GetCompany(r.Context(), workspaceID(r), id)
+	if err != nil {
+		if err == storage.ErrCompanyNotFound {
+			http.Error(w, "Company not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to retrieve company", http.StatusInternalServerError)
+		}
+		return
+	}
+	applications, err := storage.CompanyApplications(r.Context(), company)
+	if err != nil {
+		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
+		return
+	}
+	companies, err := storage.ListCompanies(r.Context(), workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve companies", http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, "company", TemplateData{
+		Title:        company.Name,
+		Company:      &models.CompanySummary{Company: *company, History: models.NewCompanyHistory(applications)},
+		Companies:    otherCompanies(companies, company.ID),
+		Applications: applications,
+		BackURL:      "/companies",
+		TagColors:    tagColors(r, workspaceID(r)),
+	})
+}
+
+// otherCompanies returns the companies other than id, which it can be merged with
+func otherCompanies(companies []models.CompanySummary, id string) []models.CompanySummary {
+	others := make([]models.CompanySummary, 0, len(companies))
+	for _, c := range companies {
+		if c.ID != id {
+			others = append(others, c)
+		}
+	}
+	return others
+}
+
+// companyDetailsFromForm reads the details of a company from a form; aliases are
+// separated by commas
+func companyDetailsFromForm(r *http.Request) models.CompanyDetails {
+	var aliases []string
+	for _, alias := range strings.Split(r.FormValue("aliases"), ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return models.CompanyDetails{
+		Name:    r.FormValue("name"),
+		Aliases: aliases,
+		Website: r.FormValue("website"),
+		Size:    r.FormValue("size"),
+		Notes:   r.FormValue("notes"),
+	}
+}
+
+// HtmxCompanyHandler shows, edits, merges and deletes a company from its page:
+//
+//	GET    /htmx/companies/{id}         show the company's details
+//	PUT    /htmx/companies/{id}         update the company's details
+//	POST   /htmx/companies/{id}/merge   merge the company in the form into this one
+//	DELETE /htmx/companies/{id}         delete a company without applications
+func HtmxCompanyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/htmx/companies/"), "/")
+	canEdit := auth.HasRole(ctx, models.RoleEditor)
+	if r.Method != http.MethodGet && !canEdit {
+		http.Error(w, "Your role in this workspace does not allow editing companies", http.StatusForbidden)
+		return
+	}
+
+	company, err := storage.GetCompany(ctx, workspaceID(r), id)
+	if err != nil {
+		if err == storage.ErrCompanyNotFound {
+			http.Error(w, "Company not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to retrieve company", http.StatusInternalServerError)
+		}
+		return
+	}
+	data := map[string]interface{}{
+		"CanEdit": canEdit,
+	}
+
+	switch {
+	case r.Method == http.MethodGet && action == "":
+
+	case r.Method == http.MethodPut && action == "":
+		var nameErr *storage.CompanyNameError
+		if err := company.Update(companyDetailsFromForm(r)); err != nil {
+			data["Error"] = err.Error()
+		} else if err := storage.SaveCompany(ctx, company); errors.As(err, &nameErr) {
+			data["Error"] = nameErr.Error()
+		} else if err != nil {
+			slog.ErrorContext(ctx, "failed to save company", "companyId", id, "error", err)
+			data["Error"] = "Failed to save company"
+		} else {
+			auth.Audit(ctx, company.WorkspaceID, models.AuditCompanyUpdated, company.ID, company.Name)
+		}
+		if data["Error"] != nil {
+			// Show the stored details again rather than the rejected ones
+			if company, err = storage.GetCompany(ctx, workspaceID(r), id); err != nil {
+				http.Error(w, "Failed to retrieve company", http.StatusInternalServerError)
+				return
+			}
+		}
+
+	case r.Method == http.MethodPost && action == "merge":
+		merged, _, err := storage.MergeCompanies(ctx, workspaceID(r), id, r.FormValue("companyId"))
+		if err != nil {
+			slog.WarnContext(ctx, "failed to merge companies", "companyId", id, "error", err)
+			data["Error"] = "Failed to merge companies"
+			break
+		}
+		auth.Audit(ctx, merged.WorkspaceID, models.AuditCompaniesMerged, merged.ID, merged.Name)
+		// The merged company's applications now belong on this page
+		w.Header().Set("HX-Refresh", "true")
+		w.WriteHeader(http.StatusOK)
+		return
+
+	case r.Method == http.MethodDelete && action == "":
+		deleted, err := storage.DeleteCompany(ctx, workspaceID(r), id)
+		if err == storage.ErrCompanyInUse {
+			data["Error"] = "Companies with applications can't be deleted; merge them into another company instead"
+			break
+		} else if err != nil {
+			slog.WarnContext(ctx, "failed to delete company", "companyId", id, "error", err)
+			data["Error"] = "Failed to delete company"
+			break
+		}
+		auth.Audit(ctx, deleted.WorkspaceID, models.AuditCompanyDeleted, deleted.ID, deleted.Name)
+		w.Header().Set("HX-Redirect", "/companies")
+		w.WriteHeader(http.StatusOK)
+		return
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	applications, err := storage.CompanyApplications(ctx, company)
+	if err != nil {
+		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
+		return
+	}
+	companies, err := storage.ListCompanies(ctx, workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve companies", http.StatusInternalServerError)
+		return
+	}
+	data["Company"] = models.CompanySummary{Company: *company, History: models.NewCompanyHistory(applications)}
+	data["Companies"] = otherCompanies(companies, company.ID)
+	data["Sizes"] = models.CompanySizes
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/companies/details.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// ContactsHandler handles the contacts page
+func ContactsHandler(w http.ResponseWriter, r *http.Request) {
+	renderTemplate(w, r, "contacts", TemplateData{
+		Title: "Contacts",
+	})
+}
+
+// contactDetailsFromForm reads the details of a contact from a form
+func contactDetailsFromForm(r *http.Request) models.ContactDetails {
+	return models.ContactDetails{
+		Name:        r.FormValue("name"),
+		Role:        r.FormValue("role"),
+		Company:     r.FormValue("company"),
+		Email:       r.FormValue("email"),
+		Phone:       r.FormValue("phone"),
+		LinkedInURL: r.FormValue("linkedinUrl"),
+		Notes:       r.FormValue("notes"),
+	}
+}
+
+// applicationNames maps the IDs of a workspace's applications to "Company - Position"
+func applicationNames(r *http.Request) (map[string]string, error) {
+	applications, err := storage.GetAllApplications(r.Context(), workspaceID(r))
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(applications))
+	for _, app := range applications {
+		names[app.ID] = app.Company + " - " + app.Position
+	}
+	return names, nil
+}
+
+// HtmxContactsHandler searches, adds and deletes the contacts of the current workspace:
+//
+//	GET    /htmx/contacts?q=     list contacts matching the query
+//	POST   /htmx/contacts        add a contact
+//	DELETE /htmx/contacts/{id}   delete a contact
+func HtmxContactsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	canEdit := auth.HasRole(ctx, models.RoleEditor)
+	data := map[string]interface{}{
+		"CanEdit": canEdit,
+	}
+	if r.Method != http.MethodGet && !canEdit {
+		http.Error(w, "Your role in this workspace does not allow editing contacts", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		contact, err := models.NewContact(workspaceID(r), contactDetailsFromForm(r))
+		if err != nil {
+			data["Error"] = err.Error()
+			break
+		}
+		if err := storage.CreateContact(ctx, contact); err != nil {
+			slog.ErrorContext(ctx, "failed to save contact", "error", err)
+			data["Error"] = "Failed to save contact"
+			break
+		}
+		auth.Audit(ctx, contact.WorkspaceID, models.AuditContactCreated, contact.ID, contact.Name)
+
+	case http.MethodDelete:
+		id := strings.TrimPrefix(r.URL.Path, "/htmx/contacts/")
+		contact, err := storage.DeleteContact(ctx, workspaceID(r), id)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to delete contact", "contactId", id, "error", err)
+			data["Error"] = "Failed to delete contact"
+			break
+		}
+		auth.Audit(ctx, contact.WorkspaceID, models.AuditContactDeleted, contact.ID, contact.Name)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	contacts, err := storage.ListContacts(ctx, workspaceID(r), r.URL.Query().Get("q"))
+	if err != nil {
+		http.Error(w, "Failed to retrieve contacts", http.StatusInternalServerError)
+		return
+	}
+	names, err := applicationNames(r)
+	if err != nil {
+		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
+		return
+	}
+	data["Contacts"] = contacts
+	data["Applications"] = names
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/contacts/list.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// HtmxApplicationContactsHandler lists, links and unlinks the contacts of an application:
+//
+//	GET    /htmx/applications/{id}/contacts               list linked contacts
+//	POST   /htmx/applications/{id}/contacts               link the contact in the form
+//	DELETE /htmx/applications/{id}/contacts/{contactId}   unlink a contact
+func HtmxApplicationContactsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rest := strings.TrimPrefix(r.URL.Path, "/htmx/applications/")
+	id, contactID, found := strings.Cut(rest, "/contacts")
+	contactID = strings.TrimPrefix(contactID, "/")
+	if !found || id == "" || strings.Contains(id, "/") || strings.Contains(contactID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	if _, err := storage.GetApplicationByID(ctx, workspaceID(r), id); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Application not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to retrieve application", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	canEdit := auth.HasRole(ctx, models.RoleEditor)
+	data := map[string]interface{}{
+		"ApplicationID": id,
+		"CanEdit":       canEdit,
+	}
+	if r.Method != http.MethodGet && !canEdit {
+		http.Error(w, "Your role in this workspace does not allow editing contacts", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && contactID == "":
+
+	case r.Method == http.MethodPost && contactID == "":
+		contact, err := storage.LinkContact(ctx, workspaceID(r), r.FormValue("contactId"), id)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to link contact", "error", err)
+			data["Error"] = "Failed to link contact"
+			break
+		}
+		auth.Audit(ctx, contact.WorkspaceID, models.AuditContactLinked, id, contact.Name)
+
+	case r.Method == http.MethodDelete && contactID != "":
+		contact, err := storage.UnlinkContact(ctx, workspaceID(r), contactID, id)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to unlink contact", "contactId", contactID, "error", err)
+			data["Error"] = "Failed to unlink contact"
+			break
+		}
+		auth.Audit(ctx, contact.WorkspaceID, models.AuditContactUnlinked, id, contact.Name)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	contacts, err := storage.ListContacts(ctx, workspaceID(r), "")
+	if err != nil {
+		http.Error(w, "Failed to retrieve contacts", http.StatusInternalServerError)
+		return
+	}
+	var linked, unlinked []models.Contact
+	for _, c := range contacts {
+		if c.IsLinked(id) {
+			linked = append(linked, c)
+		} else {
+			unlinked = append(unlinked, c)
+		}
+	}
+	data["Contacts"] = linked
+	data["Unlinked"] = unlinked
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/contacts/application.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// fieldDefinitions returns the custom fields of a workspace; failing to load them only
+// hides them
+func fieldDefinitions(r *http.Request, workspaceID string) []models.FieldDefinition {
+	definitions, err := storage.ListFieldDefinitions(r.Context(), workspaceID)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to load custom fields", "error", err)
+		return nil
+	}
+	return definitions
+}
+
+// fieldParams reads the custom field filter of a list request, a field key in
+// fieldKey and a value in fieldValue; unknown fields and values that don't fit the
+// field are ignored
+func fieldParams(r *http.Request, workspaceID string) []models.FieldFilter {
+	key := r.URL.Query().Get("fieldKey")
+	value := strings.TrimSpace(r.URL.Query().Get("fieldValue"))
+	if key == "" || value == "" {
+		return nil
+	}
+	definition, ok := models.FindField(fieldDefinitions(r, workspaceID), key)
+	if !ok {
+		return nil
+	}
+	filter, err := models.NewFieldFilter(definition, value)
+	if err != nil {
+		return nil
+	}
+	return []models.FieldFilter{filter}
+}
+
+// HtmxFieldsHandler lists the custom fields of the current workspace; editors add and
+// delete them through the API
+func HtmxFieldsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	definitions, err := storage.ListFieldDefinitions(r.Context(), workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve custom fields", http.StatusInternalServerError)
+		return
+	}
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/fields/list.html"))
+	if err := tmpl.Execute(w, map[string]interface{}{
+		"CanEdit": auth.HasRole(r.Context(), models.RoleEditor),
+		"Fields":  definitions,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -1,13 +1,18 @@
 package ui
 
 import (
+	"fmt"
 	"html/template"
+	"log/slog"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"ApplicationTracker/auth"
 	"ApplicationTracker/models"
+	"ApplicationTracker/security"
 	"ApplicationTracker/storage"
 )
 
@@ -22,71 +27,152 @@ type TemplateData struct {
 	Tags         string
 	Status       string
 	Page         int
+	CurrentUser  *models.User
+	Username     string
+	Next         string
+	AllowSignup  bool
+	// ReadOnly hides edit and delete controls, for sharing links
+	ReadOnly bool
+	// ListURL is the base URL the list page loads results from
+	ListURL string
+	// BackURL is where the detail page links back to
+	BackURL string
+	// Workspace is the workspace being viewed and Workspaces all the user can switch to
+	Workspace  *models.Workspace
+	Workspaces []models.Workspace
+	// ShowComments loads the comments section of the detail page. ShowPrivate shows
+	// what sharing links never do: the company link, compensation, custom fields and
+	// the interviews, reminders, contacts and notes.
+	ShowComments bool
+	ShowPrivate  bool
+	// TagColors maps tag names to their chip colors
+	TagColors map[string]string
+	// Companies lists the workspace's companies and Company is the one being viewed
+	Companies []models.CompanySummary
+	Company   *models.CompanySummary
+	// Duplicates lists applications that likely duplicate the one on the detail page
+	Duplicates []models.Application
+	// StaleSettings decide which applications the needs attention page lists
+	StaleSettings *models.StaleSettings
+	// Offers are the offers on the offer comparison page
+	Offers []models.OfferSummary
+	// FieldDefinitions are the workspace's custom fields, for the application form,
+	// detail page and list filters
+	FieldDefinitions []models.FieldDefinition
+	// CSRFToken must be sent with every state-changing request and CSPNonce
+	// marks inline scripts allowed by the Content-Security-Policy
+	CSRFToken string
+	CSPNonce  string
 }
 
-// renderTemplate renders a template with the given data
-func renderTemplate(w http.ResponseWriter, tmpl string, data TemplateData) {
-	// Add current year to all template data
+// pageTemplates maps page names to the template file that defines their "content" block
+var pageTemplates = map[string]string{
+	"index":     "templates/pages/index.html",
+	"list":      "templates/pages/applications/list.html",
+	"form":      "templates/pages/applications/form.html",
+	"detail":    "templates/pages/applications/detail.html",
+	"login":     "templates/pages/auth/login.html",
+	"register":  "templates/pages/auth/register.html",
+	"settings":  "templates/pages/settings.html",
+	"contacts":  "templates/pages/contacts.html",
+	"companies": "templates/pages/companies/list.html",
+	"company":   "templates/pages/companies/detail.html",
+	"attention": "templates/pages/attention.html",
+	"offers":    "templates/pages/offers.html",
+}
+
+// renderTemplate renders a page inside the base layout
+func renderTemplate(w http.ResponseWriter, r *http.Request, tmpl string, data TemplateData) {
+	// Add current year, user and workspace to all template data
 	data.CurrentYear = time.Now().Year()
+	data.CurrentUser = auth.UserFromContext(r.Context())
+	data.Workspace = auth.WorkspaceFromContext(r.Context())
+	data.CSRFToken = auth.CSRFToken(r.Context())
+	data.CSPNonce = security.Nonce(r.Context())
+	if data.CurrentUser != nil {
+		workspaces, err := auth.ListWorkspaces(r.Context(), data.CurrentUser)
+		if err != nil {
+			slog.WarnContext(r.Context(), "failed to list workspaces", "error", err)
+		}
+		data.Workspaces = workspaces
+	}
+	// Members who can't edit see the same read-only pages as sharing links
+	if data.Workspace != nil && !data.Workspace.Can(models.RoleEditor) {
+		data.ReadOnly = true
+	}
 
-	// Parse templates
-	templates := template.Must(template.ParseGlob("templates/layouts/*.html"))
-	templates = template.Must(templates.ParseGlob("templates/pages/*.html"))
-	templates = template.Must(templates.ParseGlob("templates/pages/*/*.html"))
-	templates = template.Must(templates.ParseGlob("templates/partials/*.html"))
+	pageFile, ok := pageTemplates[tmpl]
+	if !ok {
+		http.Error(w, "Unknown page: "+tmpl, http.StatusInternalServerError)
+		return
+	}
+
+	// Each page defines its own "content" block, so pages are parsed separately
+	templates, err := template.ParseFiles(
+		"templates/layouts/base.html",
+		"templates/partials/header.html",
+		"templates/partials/footer.html",
+		pageFile,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	// Execute template
-	// First set the content template, then execute the base template
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := templates.ExecuteTemplate(w, "base.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// templateGlobs lists every template file used by the UI
+var templateGlobs = []string{
+	"templates/layouts/*.html",
+	"templates/partials/*.html",
+	"templates/pages/*.html",
+	"templates/pages/*/*.html",
+	"templates/htmx/*/*.html",
+}
 
-	// Use the tmpl parameter to determine which template to execute
-	// For the form template, we need to execute the form.html template directly
-	if tmpl == "form" {
-		// Look for the form template in the pages/applications directory
-		formTemplate := template.Must(template.ParseFiles(
-			"templates/layouts/base.html",
-			"templates/partials/header.html",
-			"templates/partials/footer.html",
-			"templates/pages/applications/form.html",
-		))
-		err := formTemplate.ExecuteTemplate(w, "base.html", data)
+// ValidateTemplates parses every template file and reports the first error
+func ValidateTemplates() error {
+	found := 0
+	for _, pattern := range templateGlobs {
+		files, err := filepath.Glob(pattern)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return fmt.Errorf("invalid template pattern %q: %w", pattern, err)
 		}
-		return
-	} else if tmpl == "detail" {
-		// Look for the detail template in the pages/applications directory
-		detailTemplate := template.Must(template.ParseFiles(
-			"templates/layouts/base.html",
-			"templates/partials/header.html",
-			"templates/partials/footer.html",
-			"templates/pages/applications/detail.html",
-		))
-		err := detailTemplate.ExecuteTemplate(w, "base.html", data)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		for _, file := range files {
+			if _, err := template.ParseFiles(file); err != nil {
+				return fmt.Errorf("failed to parse template: %w", err)
+			}
+			found++
 		}
-		return
 	}
-
-	// For other templates, execute the base.html template
-	err := templates.ExecuteTemplate(w, "base.html", data)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if found == 0 {
+		return fmt.Errorf("no templates found")
 	}
+	return nil
+}
+
+// workspaceID returns the ID of the workspace the request acts on; handlers run behind requireLogin
+func workspaceID(r *http.Request) string {
+	return auth.WorkspaceFromContext(r.Context()).ID
 }
 
 // HomeHandler handles the home page
 func HomeHandler(w http.ResponseWriter, r *http.Request) {
-	renderTemplate(w, "content", TemplateData{
+	renderTemplate(w, r, "index", TemplateData{
 		Title: "Home",
 	})
 }
 
 // ApplicationsListHandler handles the applications list page
 func ApplicationsListHandler(w http.ResponseWriter, r *http.Request) {
-	renderTemplate(w, "content", TemplateData{
-		Title: "Applications",
+	renderTemplate(w, r, "list", TemplateData{
+		Title:            "Applications",
+		ListURL:          "/htmx/applications",
+		FieldDefinitions: fieldDefinitions(r, workspaceID(r)),
 	})
 }
 
@@ -104,7 +190,7 @@ func ApplicationDetailHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get application
-	application, err := storage.GetApplicationByID(id)
+	application, err := storage.GetApplicationByID(r.Context(), workspaceID(r), id)
 	if err != nil {
 		if err == storage.ErrNotFound {
 			http.Error(w, "Application not found", http.StatusNotFound)
@@ -114,24 +200,46 @@ func ApplicationDetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderTemplate(w, "content", TemplateData{
-		Title:       application.Company + " - " + application.Position,
-		Application: application,
+	duplicates, err := storage.FindDuplicates(r.Context(), workspaceID(r), application)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to check for duplicate applications", "id", id, "error", err)
+	}
+
+	renderTemplate(w, r, "detail", TemplateData{
+		Title:            application.Company + " - " + application.Position,
+		Application:      application,
+		BackURL:          "/applications",
+		ShowComments:     true,
+		ShowPrivate:      true,
+		Duplicates:       duplicates,
+		TagColors:        tagColors(r, workspaceID(r)),
+		FieldDefinitions: fieldDefinitions(r, workspaceID(r)),
 	})
 }
 
 // NewApplicationHandler handles the new application page
 func NewApplicationHandler(w http.ResponseWriter, r *http.Request) {
-	renderTemplate(w, "form", TemplateData{
-		Title:       "Add New Application",
-		Application: &models.Application{}, // Pass an empty application object
+	if !auth.HasRole(r.Context(), models.RoleEditor) {
+		http.Error(w, "Your role in this workspace does not allow adding applications", http.StatusForbidden)
+		return
+	}
+
+	renderTemplate(w, r, "form", TemplateData{
+		Title:            "Add New Application",
+		Application:      &models.Application{}, // Pass an empty application object
+		FieldDefinitions: fieldDefinitions(r, workspaceID(r)),
 	})
 }
 
 // ApplicationEditHandler handles the edit application page
 func ApplicationEditHandler(w http.ResponseWriter, r *http.Request, id string) {
+	if !auth.HasRole(r.Context(), models.RoleEditor) {
+		http.Error(w, "Your role in this workspace does not allow editing applications", http.StatusForbidden)
+		return
+	}
+
 	// Get application
-	application, err := storage.GetApplicationByID(id)
+	application, err := storage.GetApplicationByID(r.Context(), workspaceID(r), id)
 	if err != nil {
 		if err == storage.ErrNotFound {
 			http.Error(w, "Application not found", http.StatusNotFound)
@@ -141,17 +249,74 @@ func ApplicationEditHandler(w http.ResponseWriter, r *http.Request, id string) {
 		return
 	}
 
-	renderTemplate(w, "content", TemplateData{
-		Title:       "Edit Application - " + application.Company,
-		Application: application,
+	renderTemplate(w, r, "form", TemplateData{
+		Title:            "Edit Application - " + application.Company,
+		Application:      application,
+		FieldDefinitions: fieldDefinitions(r, workspaceID(r)),
 	})
 }
 
 // HtmxApplicationsHandler handles HTMX requests for applications list
 func HtmxApplicationsHandler(w http.ResponseWriter, r *http.Request) {
-	// Get query parameters
+	query, tags := searchParams(r)
+
+	// Get applications
+	applications, err := storage.SearchApplications(r.Context(), workspaceID(r), query, tags, true)
+	if err != nil {
+		http.Error(w, "Failed to search applications", http.StatusInternalServerError)
+		return
+	}
+	applications = models.FilterBySalary(applications, salaryParams(r))
+	applications = models.FilterByLocation(applications, locationParams(r))
+	applications = models.FilterByFields(applications, fieldParams(r, workspaceID(r)))
+
+	renderApplicationList(w, r, applications, listView{
+		ReadOnly:  !auth.HasRole(r.Context(), models.RoleEditor),
+		DetailURL: "/applications/",
+		TagColors: tagColors(r, workspaceID(r)),
+	})
+}
+
+// listView controls how the applications list partial is rendered
+type listView struct {
+	// ReadOnly hides the controls that change applications
+	ReadOnly bool
+	// DetailURL is the prefix of links to application details
+	DetailURL string
+	// TagColors maps tag names to their chip colors
+	TagColors map[string]string
+}
+
+// tagColors returns the tag colors of a workspace for rendering chips; on failure tags
+// are shown in the default color
+func tagColors(r *http.Request, workspaceID string) map[string]string {
+	colors, err := storage.TagColors(r.Context(), workspaceID)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to load tag colors", "error", err)
+		return map[string]string{}
+	}
+	return colors
+}
+
+// searchParams parses the text query and comma separated tags of a list request
+func searchParams(r *http.Request) (string, []string) {
 	query := r.URL.Query().Get("q")
 	tagsParam := r.URL.Query().Get("tags")
+
+	// Parse tags
+	var tags []string
+	if tagsParam != "" {
+		tags = strings.Split(tagsParam, ",")
+		for i, tag := range tags {
+			tags[i] = strings.TrimSpace(tag)
+		}
+	}
+	return query, tags
+}
+
+// renderApplicationList filters applications by status, paginates them and renders the list partial
+func renderApplicationList(w http.ResponseWriter, r *http.Request, applications []models.Application, view listView) {
+	// Get query parameters
 	status := r.URL.Query().Get("status")
 	pageStr := r.URL.Query().Get("page")
 	pageSizeStr := r.URL.Query().Get("pageSize")
@@ -180,22 +345,6 @@ func HtmxApplicationsHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Parse tags
-	var tags []string
-	if tagsParam != "" {
-		tags = strings.Split(tagsParam, ",")
-		for i, tag := range tags {
-			tags[i] = strings.TrimSpace(tag)
-		}
-	}
-
-	// Get applications
-	applications, err := storage.SearchApplications(query, tags)
-	if err != nil {
-		http.Error(w, "Failed to search applications", http.StatusInternalServerError)
-		return
-	}
-
 	// Filter by status if provided
 	if status != "" {
 		var filtered []models.Application
@@ -241,8 +390,11 @@ func HtmxApplicationsHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Render template
 	tmpl := template.Must(template.ParseFiles("templates/htmx/applications/list.html"))
-	err = tmpl.Execute(w, map[string]interface{}{
+	err := tmpl.Execute(w, map[string]interface{}{
 		"Applications": applications,
+		"ReadOnly":     view.ReadOnly,
+		"DetailURL":    view.DetailURL,
+		"TagColors":    view.TagColors,
 		"Pagination": map[string]interface{}{
 			"CurrentPage": page,
 			"PageSize":    pageSize,
@@ -260,7 +412,7 @@ func HtmxApplicationsHandler(w http.ResponseWriter, r *http.Request) {
 // HtmxApplicationsCountHandler handles HTMX requests for applications count
 func HtmxApplicationsCountHandler(w http.ResponseWriter, r *http.Request) {
 	// Get applications
-	applications, err := storage.GetAllApplications()
+	applications, err := storage.GetAllApplications(r.Context(), workspaceID(r))
 	if err != nil {
 		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
 		return
@@ -276,7 +428,7 @@ func HtmxStatsHandler(w http.ResponseWriter, r *http.Request) {
 	statType := strings.TrimPrefix(r.URL.Path, "/htmx/stats/")
 
 	// Get applications
-	applications, err := storage.GetAllApplications()
+	applications, err := storage.GetAllApplications(r.Context(), workspaceID(r))
 	if err != nil {
 		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
 		return
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// upcomingInterviewDays is how far ahead the home page lists interviews
+const upcomingInterviewDays = 14
+
+// datetimeLocalLayout is the format of datetime-local form inputs
+const datetimeLocalLayout = "2006-01-02T15:04"
+
+// interviewDetailsFromForm reads the details of an interview from the scheduling form;
+// the start time is entered in the interview's time zone
+func interviewDetailsFromForm(r *http.Request) (models.InterviewDetails, string) {
+	details := models.InterviewDetails{
+		Round:     r.FormValue("round"),
+		TimeZone:  strings.TrimSpace(r.FormValue("timeZone")),
+		Location:  r.FormValue("location"),
+		VideoURL:  r.FormValue("videoUrl"),
+		PrepNotes: r.FormValue("prepNotes"),
+	}
+	details.Interviewers = strings.Split(r.FormValue("interviewers"), ",")
+
+	loc := time.UTC
+	if details.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(details.TimeZone); err != nil {
+			return details, "Unknown time zone: " + details.TimeZone
+		}
+	}
+	start, err := time.ParseInLocation(datetimeLocalLayout, r.FormValue("startsAt"), loc)
+	if err != nil {
+		return details, "Start time is required"
+	}
+	details.StartsAt = start
+	if minutes, err := strconv.Atoi(r.FormValue("duration")); err == nil && minutes > 0 {
+		details.EndsAt = start.Add(time.Duration(minutes) * time.Minute)
+	}
+	return details, ""
+}
+
+// HtmxInterviewsHandler lists, schedules, updates and deletes the interviews of an application:
+//
+//	GET    /htmx/applications/{id}/interviews                  list
+//	POST   /htmx/applications/{id}/interviews                  schedule an interview
+//	PUT    /htmx/applications/{id}/interviews/{interviewId}    set the outcome
+//	DELETE /htmx/applications/{id}/interviews/{interviewId}    delete an interview
+func HtmxInterviewsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rest := strings.TrimPrefix(r.URL.Path, "/htmx/applications/")
+	id, interviewID, found := strings.Cut(rest, "/interviews")
+	interviewID = strings.TrimPrefix(interviewID, "/")
+	if !found || id == "" || strings.Contains(id, "/") || strings.Contains(interviewID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	application, err := storage.GetApplicationByID(ctx, workspaceID(r), id)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Application not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to retrieve application", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	canEdit := auth.HasRole(ctx, models.RoleEditor)
+	data := map[string]interface{}{
+		"ApplicationID": id,
+		"CanEdit":       canEdit,
+	}
+	if r.Method != http.MethodGet && !canEdit {
+		http.Error(w, "Your role in this workspace does not allow editing interviews", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && interviewID == "":
+
+	case r.Method == http.MethodPost && interviewID == "":
+		details, formErr := interviewDetailsFromForm(r)
+		if formErr != "" {
+			data["Error"] = formErr
+			break
+		}
+		interview, err := models.NewInterview(workspaceID(r), id, details)
+		if err != nil {
+			data["Error"] = err.Error()
+			break
+		}
+		previousStatus, err := storage.ScheduleInterview(ctx, application, interview)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to schedule interview", "error", err)
+			data["Error"] = "Failed to schedule interview"
+			break
+		}
+		auth.Audit(ctx, interview.WorkspaceID, models.AuditInterviewScheduled, id, interview.Round)
+		if previousStatus != "" {
+			auth.Audit(ctx, application.OwnerID, models.AuditApplicationStatusChanged, id, previousStatus+" -> "+application.Status)
+			// Reload the page so it shows the new status
+			w.Header().Set("HX-Refresh", "true")
+		}
+
+	case r.Method == http.MethodPut && interviewID != "":
+		interview, err := storage.GetInterview(ctx, workspaceID(r), id, interviewID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load interview", "interviewId", interviewID, "error", err)
+			data["Error"] = "Failed to update interview"
+			break
+		}
+		details := interview.InterviewDetails
+		details.Outcome = r.FormValue("outcome")
+		if err := interview.Update(details); err != nil {
+			data["Error"] = err.Error()
+			break
+		}
+		if err := storage.SaveInterview(ctx, interview); err != nil {
+			slog.WarnContext(ctx, "failed to update interview", "interviewId", interviewID, "error", err)
+			data["Error"] = "Failed to update interview"
+			break
+		}
+		auth.Audit(ctx, interview.WorkspaceID, models.AuditInterviewUpdated, id, interview.Round+" "+interview.Outcome)
+
+	case r.Method == http.MethodDelete && interviewID != "":
+		if err := storage.DeleteInterview(ctx, workspaceID(r), id, interviewID); err != nil {
+			slog.WarnContext(ctx, "failed to delete interview", "interviewId", interviewID, "error", err)
+			data["Error"] = "Failed to delete interview"
+			break
+		}
+		auth.Audit(ctx, workspaceID(r), models.AuditInterviewDeleted, id, "")
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	interviews, err := storage.ListInterviews(ctx, workspaceID(r), id)
+	if err != nil {
+		http.Error(w, "Failed to retrieve interviews", http.StatusInternalServerError)
+		return
+	}
+	data["Interviews"] = interviews
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/interviews/list.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// HtmxUpcomingInterviewsHandler lists the pending interviews of the coming days for the home page
+func HtmxUpcomingInterviewsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := time.Now()
+	interviews, err := storage.ListUpcomingInterviews(r.Context(), workspaceID(r), now, now.AddDate(0, 0, upcomingInterviewDays))
+	if err != nil {
+		http.Error(w, "Failed to retrieve interviews", http.StatusInternalServerError)
+		return
+	}
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/interviews/upcoming.html"))
+	err = tmpl.Execute(w, map[string]interface{}{
+		"Interviews": interviews,
+		"Days":       upcomingInterviewDays,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
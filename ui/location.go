@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ApplicationTracker/models"
+)
+
+// locationParams reads the work mode, place and UTC offset of a list request; an
+// unknown work mode or an offset that isn't a number is ignored
+func locationParams(r *http.Request) models.LocationFilter {
+	query := r.URL.Query()
+	filter := models.LocationFilter{Place: strings.TrimSpace(query.Get("location"))}
+	if mode := query.Get("workMode"); models.IsValidWorkMode(mode) {
+		filter.WorkMode = mode
+	}
+	if offset, err := strconv.ParseFloat(strings.TrimSpace(query.Get("utcOffset")), 64); err == nil {
+		filter.UTCOffset = &offset
+	}
+	return filter
+}
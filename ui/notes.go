@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// htmxApplicationHandler routes /htmx/applications/{id}/... to the notes, interviews,
+// contacts, reminders or comments handler
+func htmxApplicationHandler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.Contains(r.URL.Path, "/notes"):
+		HtmxNotesHandler(w, r)
+	case strings.Contains(r.URL.Path, "/interviews"):
+		HtmxInterviewsHandler(w, r)
+	case strings.Contains(r.URL.Path, "/contacts"):
+		HtmxApplicationContactsHandler(w, r)
+	case strings.Contains(r.URL.Path, "/reminders"):
+		HtmxRemindersHandler(w, r)
+	default:
+		HtmxCommentsHandler(w, r)
+	}
+}
+
+// HtmxNotesHandler lists, adds, edits and deletes the notes on an application:
+//
+//	GET    /htmx/applications/{id}/notes[?edit={noteId}]   list, editing one note inline
+//	POST   /htmx/applications/{id}/notes                   add a note
+//	PUT    /htmx/applications/{id}/notes/{noteId}          edit a note
+//	DELETE /htmx/applications/{id}/notes/{noteId}          delete a note
+func HtmxNotesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rest := strings.TrimPrefix(r.URL.Path, "/htmx/applications/")
+	id, noteID, found := strings.Cut(rest, "/notes")
+	noteID = strings.TrimPrefix(noteID, "/")
+	if !found || id == "" || strings.Contains(id, "/") || strings.Contains(noteID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	if _, err := storage.GetApplicationByID(ctx, workspaceID(r), id); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Application not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to retrieve application", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	canEdit := auth.HasRole(ctx, models.RoleEditor)
+	data := map[string]interface{}{
+		"ApplicationID": id,
+		"CanEdit":       canEdit,
+		"Editing":       "",
+	}
+	if r.Method != http.MethodGet && !canEdit {
+		http.Error(w, "Your role in this workspace does not allow editing notes", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && noteID == "":
+		if canEdit {
+			data["Editing"] = r.URL.Query().Get("edit")
+		}
+
+	case r.Method == http.MethodPost && noteID == "":
+		body := strings.TrimSpace(r.FormValue("body"))
+		if body == "" || len(body) > models.MaxNoteLength {
+			data["Error"] = fmt.Sprintf("Note must be between 1 and %d characters", models.MaxNoteLength)
+			break
+		}
+		note := models.NewNote(workspaceID(r), id, auth.UserFromContext(ctx), body)
+		if err := storage.CreateNote(ctx, note); err != nil {
+			slog.ErrorContext(ctx, "failed to save note", "error", err)
+			data["Error"] = "Failed to save note"
+			break
+		}
+		auth.Audit(ctx, note.WorkspaceID, models.AuditNoteCreated, id, "")
+
+	case r.Method == http.MethodPut && noteID != "":
+		body := strings.TrimSpace(r.FormValue("body"))
+		if body == "" || len(body) > models.MaxNoteLength {
+			data["Error"] = fmt.Sprintf("Note must be between 1 and %d characters", models.MaxNoteLength)
+			data["Editing"] = noteID
+			break
+		}
+		if _, err := storage.UpdateNote(ctx, workspaceID(r), id, noteID, body); err != nil {
+			slog.WarnContext(ctx, "failed to update note", "noteId", noteID, "error", err)
+			data["Error"] = "Failed to update note"
+			break
+		}
+		auth.Audit(ctx, workspaceID(r), models.AuditNoteUpdated, id, "")
+
+	case r.Method == http.MethodDelete && noteID != "":
+		if err := storage.DeleteNote(ctx, workspaceID(r), id, noteID); err != nil {
+			slog.WarnContext(ctx, "failed to delete note", "noteId", noteID, "error", err)
+			data["Error"] = "Failed to delete note"
+			break
+		}
+		auth.Audit(ctx, workspaceID(r), models.AuditNoteDeleted, id, "")
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	notes, err := storage.ListNotes(ctx, workspaceID(r), id)
+	if err != nil {
+		http.Error(w, "Failed to retrieve notes", http.StatusInternalServerError)
+		return
+	}
+	data["Notes"] = notes
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/notes/list.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// salaryParams reads the yearly base pay range and currency of a list request;
+// amounts that aren't whole numbers are ignored
+func salaryParams(r *http.Request) models.SalaryFilter {
+	query := r.URL.Query()
+	filter := models.SalaryFilter{Currency: strings.ToUpper(strings.TrimSpace(query.Get("currency")))}
+	if amount, err := strconv.ParseInt(query.Get("salaryMin"), 10, 64); err == nil && amount > 0 {
+		filter.Min = amount
+	}
+	if amount, err := strconv.ParseInt(query.Get("salaryMax"), 10, 64); err == nil && amount > 0 {
+		filter.Max = amount
+	}
+	return filter
+}
+
+// OffersHandler handles the page comparing the offers on accepted and in-progress applications
+func OffersHandler(w http.ResponseWriter, r *http.Request) {
+	applications, err := storage.GetAllApplications(r.Context(), workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, "offers", TemplateData{
+		Title:  "Compare Offers",
+		Offers: models.CompareOffers(applications),
+	})
+}
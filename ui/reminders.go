@@ -0,0 +1,315 @@
+package ui
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// snoozeHours reads the number of hours to snooze a reminder from a form, defaulting to a day
+func snoozeHours(r *http.Request) time.Duration {
+	hours, err := strconv.Atoi(r.FormValue("hours"))
+	if err != nil || hours < 1 || hours > 24*models.MaxReminderRuleDays {
+		hours = 24
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// reminderDueFromForm reads the due time of a reminder from a form; it is entered in
+// the browser's time zone
+func reminderDueFromForm(r *http.Request) (time.Time, string) {
+	loc := time.UTC
+	if name := strings.TrimSpace(r.FormValue("timeZone")); name != "" {
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			return time.Time{}, "Unknown time zone: " + name
+		}
+	}
+	due, err := time.ParseInLocation(datetimeLocalLayout, r.FormValue("dueAt"), loc)
+	if err != nil {
+		return time.Time{}, "Due time is required"
+	}
+	return due, ""
+}
+
+// HtmxRemindersHandler lists, adds, completes, snoozes and deletes the reminders on an application:
+//
+//	GET    /htmx/applications/{id}/reminders                        list
+//	POST   /htmx/applications/{id}/reminders                        add a reminder
+//	PUT    /htmx/applications/{id}/reminders/{reminderId}           mark done or not done
+//	POST   /htmx/applications/{id}/reminders/{reminderId}/snooze    snooze for the hours in the form
+//	DELETE /htmx/applications/{id}/reminders/{reminderId}           delete a reminder
+func HtmxRemindersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rest := strings.TrimPrefix(r.URL.Path, "/htmx/applications/")
+	id, reminderID, found := strings.Cut(rest, "/reminders")
+	reminderID = strings.TrimPrefix(reminderID, "/")
+	reminderID, snooze := strings.CutSuffix(reminderID, "/snooze")
+	if !found || id == "" || strings.Contains(id, "/") || strings.Contains(reminderID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	if _, err := storage.GetApplicationByID(ctx, workspaceID(r), id); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Application not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to retrieve application", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	canEdit := auth.HasRole(ctx, models.RoleEditor)
+	data := map[string]interface{}{
+		"ApplicationID": id,
+		"CanEdit":       canEdit,
+	}
+	if r.Method != http.MethodGet && !canEdit {
+		http.Error(w, "Your role in this workspace does not allow editing reminders", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && reminderID == "":
+
+	case r.Method == http.MethodPost && reminderID == "":
+		due, formErr := reminderDueFromForm(r)
+		if formErr != "" {
+			data["Error"] = formErr
+			break
+		}
+		reminder, err := models.NewReminder(workspaceID(r), id, r.FormValue("message"), due)
+		if err != nil {
+			data["Error"] = err.Error()
+			break
+		}
+		if err := storage.CreateReminder(ctx, reminder); err != nil {
+			slog.ErrorContext(ctx, "failed to save reminder", "error", err)
+			data["Error"] = "Failed to save reminder"
+			break
+		}
+		auth.Audit(ctx, reminder.WorkspaceID, models.AuditReminderCreated, id, "")
+
+	case (r.Method == http.MethodPut || r.Method == http.MethodPost && snooze) && reminderID != "":
+		reminder, err := storage.GetReminder(ctx, workspaceID(r), id, reminderID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load reminder", "reminderId", reminderID, "error", err)
+			data["Error"] = "Failed to update reminder"
+			break
+		}
+		detail := "snoozed"
+		if snooze {
+			now := time.Now()
+			err = reminder.Snooze(now.Add(snoozeHours(r)), now)
+		} else {
+			err = reminder.Update(reminder.Message, reminder.DueAt, r.FormValue("done") == "true")
+			detail = reminder.State()
+		}
+		if err != nil {
+			data["Error"] = err.Error()
+			break
+		}
+		if err := storage.SaveReminder(ctx, reminder); err != nil {
+			slog.WarnContext(ctx, "failed to update reminder", "reminderId", reminderID, "error", err)
+			data["Error"] = "Failed to update reminder"
+			break
+		}
+		auth.Audit(ctx, reminder.WorkspaceID, models.AuditReminderUpdated, id, detail)
+		w.Header().Set("HX-Trigger", "notificationsChanged")
+
+	case r.Method == http.MethodDelete && reminderID != "":
+		if err := storage.DeleteReminder(ctx, workspaceID(r), id, reminderID); err != nil {
+			slog.WarnContext(ctx, "failed to delete reminder", "reminderId", reminderID, "error", err)
+			data["Error"] = "Failed to delete reminder"
+			break
+		}
+		auth.Audit(ctx, workspaceID(r), models.AuditReminderDeleted, id, "")
+		w.Header().Set("HX-Trigger", "notificationsChanged")
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	reminders, err := storage.ListReminders(ctx, workspaceID(r), id)
+	if err != nil {
+		http.Error(w, "Failed to retrieve reminders", http.StatusInternalServerError)
+		return
+	}
+	data["Reminders"] = reminders
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/reminders/list.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// HtmxNotificationsHandler shows the notifications in the header and acts on them:
+//
+//	GET  /htmx/notifications                 list notifications with the unread count
+//	POST /htmx/notifications/read            mark all notifications read
+//	POST /htmx/notifications/{id}/read       mark a notification read
+//	POST /htmx/notifications/{id}/done       mark the notification's reminder done
+//	POST /htmx/notifications/{id}/snooze     snooze the notification's reminder
+func HtmxNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	canEdit := auth.HasRole(ctx, models.RoleEditor)
+	data := map[string]interface{}{
+		"CanEdit": canEdit,
+	}
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/htmx/notifications"), "/")
+	id, action, _ := strings.Cut(rest, "/")
+	if id == "read" && action == "" {
+		id, action = "", "read"
+	}
+
+	switch {
+	case r.Method == http.MethodGet && rest == "":
+
+	case r.Method == http.MethodPost && action == "read":
+		var err error
+		if id == "" {
+			err = storage.MarkAllNotificationsRead(ctx, workspaceID(r))
+		} else {
+			err = storage.MarkNotificationRead(ctx, workspaceID(r), id)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "failed to mark notifications read", "error", err)
+			data["Error"] = "Failed to update notifications"
+		}
+
+	case r.Method == http.MethodPost && (action == "done" || action == "snooze"):
+		if !canEdit {
+			http.Error(w, "Your role in this workspace does not allow editing reminders", http.StatusForbidden)
+			return
+		}
+		notification, err := storage.GetNotification(ctx, workspaceID(r), id)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load notification", "notificationId", id, "error", err)
+			data["Error"] = "Failed to update reminder"
+			break
+		}
+		reminder, err := storage.GetReminder(ctx, workspaceID(r), notification.ApplicationID, notification.ReminderID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load reminder", "reminderId", notification.ReminderID, "error", err)
+			data["Error"] = "Failed to update reminder"
+			break
+		}
+		now := time.Now()
+		if action == "snooze" {
+			err = reminder.Snooze(now.Add(snoozeHours(r)), now)
+		} else {
+			err = reminder.Update(reminder.Message, reminder.DueAt, true)
+		}
+		if err == nil {
+			err = storage.SaveReminder(ctx, reminder)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "failed to update reminder", "reminderId", reminder.ID, "error", err)
+			data["Error"] = "Failed to update reminder"
+			break
+		}
+		detail := reminder.State()
+		if action == "snooze" {
+			detail = "snoozed"
+		}
+		auth.Audit(ctx, reminder.WorkspaceID, models.AuditReminderUpdated, reminder.ApplicationID, detail)
+		w.Header().Set("HX-Trigger", "remindersChanged")
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	notifications, unread, err := storage.ListNotifications(ctx, workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve notifications", http.StatusInternalServerError)
+		return
+	}
+	data["Notifications"] = notifications
+	data["Unread"] = unread
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/notifications/list.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// HtmxReminderRulesHandler lists, adds, toggles and deletes the reminder rules of the workspace:
+//
+//	GET    /htmx/reminders/rules        list
+//	POST   /htmx/reminders/rules        add a rule
+//	PUT    /htmx/reminders/rules/{id}   enable or disable a rule
+//	DELETE /htmx/reminders/rules/{id}   delete a rule
+func HtmxReminderRulesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	canEdit := auth.HasRole(ctx, models.RoleEditor)
+	data := map[string]interface{}{
+		"CanEdit":  canEdit,
+		"Statuses": []string{models.ApplicationStatus.Applied, models.ApplicationStatus.InProgress},
+	}
+	if r.Method != http.MethodGet && !canEdit {
+		http.Error(w, "Your role in this workspace does not allow editing reminder rules", http.StatusForbidden)
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/htmx/reminders/rules"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && id == "":
+
+	case r.Method == http.MethodPost && id == "":
+		days, _ := strconv.Atoi(r.FormValue("days"))
+		rule, err := models.NewReminderRule(workspaceID(r), r.FormValue("status"), days, r.FormValue("message"))
+		if err != nil {
+			data["Error"] = err.Error()
+			break
+		}
+		if err := storage.CreateReminderRule(ctx, rule); err == storage.ErrTooManyReminderRules {
+			data["Error"] = "A workspace can have at most " + strconv.Itoa(storage.MaxReminderRules) + " reminder rules"
+		} else if err != nil {
+			slog.ErrorContext(ctx, "failed to save reminder rule", "error", err)
+			data["Error"] = "Failed to save reminder rule"
+		}
+
+	case r.Method == http.MethodPut && id != "":
+		rule, err := storage.GetReminderRule(ctx, workspaceID(r), id)
+		if err == nil {
+			rule.Enabled = r.FormValue("enabled") == "true"
+			err = storage.SaveReminderRule(ctx, rule)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "failed to update reminder rule", "ruleId", id, "error", err)
+			data["Error"] = "Failed to update reminder rule"
+		}
+
+	case r.Method == http.MethodDelete && id != "":
+		if err := storage.DeleteReminderRule(ctx, workspaceID(r), id); err != nil {
+			slog.WarnContext(ctx, "failed to delete reminder rule", "ruleId", id, "error", err)
+			data["Error"] = "Failed to delete reminder rule"
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rules, err := storage.ListReminderRules(ctx, workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve reminder rules", http.StatusInternalServerError)
+		return
+	}
+	data["Rules"] = rules
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/reminders/rules.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
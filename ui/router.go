@@ -2,23 +2,71 @@ package ui
 
 import (
 	"net/http"
+	"time"
+
+	"ApplicationTracker/config"
+	"ApplicationTracker/ratelimit"
 )
 
+// maxTags is the most tags an application can have; set by SetupUIRouter
+var maxTags = 20
+
 // SetupUIRouter sets up the UI routes
-func SetupUIRouter(mux *http.ServeMux) {
+func SetupUIRouter(mux *http.ServeMux, cfg config.Config) {
+	maxTags = cfg.MaxTags
+	if cfg.LoginRateLimit > 0 {
+		loginLimiter = ratelimit.New(float64(cfg.LoginRateLimit)/time.Minute.Seconds(), cfg.LoginRateLimit)
+	}
+
 	// Serve static files
 	fileServer := http.FileServer(http.Dir("static"))
 	mux.Handle("/static/", http.StripPrefix("/static/", fileServer))
-	
+
+	// Authentication routes
+	mux.HandleFunc("/login", limitAttempts(LoginHandler))
+	mux.HandleFunc("/register", limitAttempts(RegisterHandler))
+	mux.HandleFunc("/logout", LogoutHandler)
+
+	// Sharing links are public; the signed token in the path grants access
+	mux.HandleFunc("/share/", ShareHandler)
+
 	// UI routes
-	mux.HandleFunc("/", HomeHandler)
-	mux.HandleFunc("/applications", ApplicationsListHandler)
-	mux.HandleFunc("/applications/new", NewApplicationHandler)
-	mux.HandleFunc("/applications/", ApplicationDetailHandler)
-	
+	mux.HandleFunc("/", requireLogin(HomeHandler))
+	mux.HandleFunc("/applications", requireLogin(ApplicationsListHandler))
+	mux.HandleFunc("/applications/new", requireLogin(NewApplicationHandler))
+	mux.HandleFunc("/applications/", requireLogin(ApplicationDetailHandler))
+	mux.HandleFunc("/contacts", requireLogin(ContactsHandler))
+	mux.HandleFunc("/companies", requireLogin(CompaniesHandler))
+	mux.HandleFunc("/companies/", requireLogin(CompanyHandler))
+	mux.HandleFunc("/attention", requireLogin(AttentionHandler))
+	mux.HandleFunc("/offers", requireLogin(OffersHandler))
+	mux.HandleFunc("/settings", requireLogin(SettingsHandler))
+	mux.HandleFunc("/workspace", requireLogin(SelectWorkspaceHandler))
+
 	// HTMX routes
-	mux.HandleFunc("/htmx/applications", HtmxApplicationsHandler)
-	mux.HandleFunc("/htmx/applications/search", HtmxApplicationsHandler)
-	mux.HandleFunc("/htmx/applications/count", HtmxApplicationsCountHandler)
-	mux.HandleFunc("/htmx/stats/", HtmxStatsHandler)
-}
\ No newline at end of file
+	mux.HandleFunc("/htmx/applications", requireLogin(HtmxApplicationsHandler))
+	mux.HandleFunc("/htmx/applications/search", requireLogin(HtmxApplicationsHandler))
+	mux.HandleFunc("/htmx/applications/count", requireLogin(HtmxApplicationsCountHandler))
+	mux.HandleFunc("/htmx/applications/", requireLogin(htmxApplicationHandler))
+	mux.HandleFunc("/htmx/stats/", requireLogin(HtmxStatsHandler))
+	mux.HandleFunc("/htmx/interviews/upcoming", requireLogin(HtmxUpcomingInterviewsHandler))
+	mux.HandleFunc("/htmx/contacts", requireLogin(HtmxContactsHandler))
+	mux.HandleFunc("/htmx/notifications", requireLogin(HtmxNotificationsHandler))
+	mux.HandleFunc("/htmx/notifications/", requireLogin(HtmxNotificationsHandler))
+	mux.HandleFunc("/htmx/reminders/rules", requireLogin(HtmxReminderRulesHandler))
+	mux.HandleFunc("/htmx/reminders/rules/", requireLogin(HtmxReminderRulesHandler))
+	mux.HandleFunc("/htmx/stale/settings", requireLogin(HtmxStaleSettingsHandler))
+	mux.HandleFunc("/htmx/companies/", requireLogin(HtmxCompanyHandler))
+	mux.HandleFunc("/htmx/contacts/", requireLogin(HtmxContactsHandler))
+	mux.HandleFunc("/htmx/tokens", requireLogin(HtmxTokensHandler))
+	mux.HandleFunc("/htmx/tokens/", requireLogin(HtmxTokensHandler))
+	mux.HandleFunc("/htmx/tags", requireLogin(HtmxTagsHandler))
+	mux.HandleFunc("/htmx/autotag", requireLogin(HtmxAutoTagHandler))
+	mux.HandleFunc("/htmx/autotag/preview", requireLogin(HtmxAutoTagHandler))
+	mux.HandleFunc("/htmx/fields", requireLogin(HtmxFieldsHandler))
+	mux.HandleFunc("/htmx/shares", requireLogin(HtmxSharesHandler))
+	mux.HandleFunc("/htmx/shares/", requireLogin(HtmxSharesHandler))
+	mux.HandleFunc("/htmx/members", requireLogin(HtmxMembersHandler))
+	mux.HandleFunc("/htmx/members/", requireLogin(HtmxMembersHandler))
+	mux.HandleFunc("/htmx/audit", requireLogin(HtmxAuditHandler))
+}
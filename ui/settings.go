@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// SettingsHandler handles the account settings page
+func SettingsHandler(w http.ResponseWriter, r *http.Request) {
+	renderTemplate(w, r, "settings", TemplateData{
+		Title: "Settings",
+	})
+}
+
+// HtmxTokensHandler lists, creates and revokes the current user's API tokens
+func HtmxTokensHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.UserFromContext(ctx)
+	data := map[string]interface{}{
+		"Scopes": models.ValidScopes,
+	}
+
+	switch {
+	case r.Method == http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Invalid form data", http.StatusBadRequest)
+			return
+		}
+		days, _ := strconv.Atoi(r.FormValue("expiresInDays"))
+		ttl := time.Duration(days) * 24 * time.Hour
+		secret, _, err := auth.IssueToken(ctx, user, r.FormValue("name"), r.Form["scopes"], ttl)
+		if err != nil {
+			data["Error"] = err.Error()
+		} else {
+			data["NewToken"] = secret
+		}
+
+	case r.Method == http.MethodDelete:
+		id := strings.TrimPrefix(r.URL.Path, "/htmx/tokens/")
+		if _, err := storage.RevokeToken(ctx, user.ID, id); err != nil {
+			slog.WarnContext(ctx, "failed to revoke token", "tokenId", id, "error", err)
+			data["Error"] = "Failed to revoke token"
+		}
+
+	case r.Method != http.MethodGet:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tokens, err := storage.ListTokens(ctx, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve tokens", http.StatusInternalServerError)
+		return
+	}
+	data["Tokens"] = tokens
+	data["Now"] = time.Now()
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/tokens/list.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
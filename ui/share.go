@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// ShareHandler serves the read-only views behind a sharing link:
+//
+//	/share/{token}                              list page
+//	/share/{token}/htmx/applications[/search]   list results
+//	/share/{token}/htmx/applications/count      result count
+//	/share/{token}/applications/{id}            detail page
+func ShareHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/share/"), "/")
+	share, err := auth.VerifyShareToken(r.Context(), token)
+	if err != nil {
+		if !errors.Is(err, auth.ErrInvalidShare) {
+			slog.ErrorContext(r.Context(), "failed to verify sharing link", "error", err)
+		}
+		http.Error(w, "Sharing link not found or expired", http.StatusNotFound)
+		return
+	}
+
+	// Keep the token out of Referer headers and search engines
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Robots-Tag", "noindex")
+
+	base := "/share/" + token
+	switch {
+	case rest == "":
+		renderTemplate(w, r, "list", TemplateData{
+			Title:    share.Name,
+			ReadOnly: true,
+			ListURL:  base + "/htmx/applications",
+		})
+
+	case rest == "htmx/applications" || rest == "htmx/applications/search":
+		applications, err := sharedApplications(r, share)
+		if err != nil {
+			http.Error(w, "Failed to search applications", http.StatusInternalServerError)
+			return
+		}
+		renderApplicationList(w, r, applications, listView{
+			ReadOnly:  true,
+			DetailURL: base + "/applications/",
+			TagColors: tagColors(r, share.OwnerID),
+		})
+
+	case rest == "htmx/applications/count":
+		applications, err := sharedApplications(r, share)
+		if err != nil {
+			http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(strconv.Itoa(len(applications))))
+
+	case strings.HasPrefix(rest, "applications/"):
+		id := strings.TrimSuffix(strings.TrimPrefix(rest, "applications/"), "/")
+		application, err := storage.GetApplicationByID(r.Context(), share.OwnerID, id)
+		if err != nil || !share.Filter.Matches(*application) {
+			if err == nil || err == storage.ErrNotFound {
+				http.Error(w, "Application not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to retrieve application", http.StatusInternalServerError)
+			}
+			return
+		}
+		renderTemplate(w, r, "detail", TemplateData{
+			Title:       application.Company + " - " + application.Position,
+			Application: application,
+			ReadOnly:    true,
+			BackURL:     base,
+			TagColors:   tagColors(r, share.OwnerID),
+		})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// sharedApplications searches the share owner's applications and keeps those the share
+// exposes; notes are private to the workspace, so the search ignores them
+func sharedApplications(r *http.Request, share *models.Share) ([]models.Application, error) {
+	query, tags := searchParams(r)
+	applications, err := storage.SearchApplications(r.Context(), share.OwnerID, query, tags, false)
+	if err != nil {
+		return nil, err
+	}
+
+	place := locationParams(r)
+	var visible []models.Application
+	for _, app := range applications {
+		if share.Filter.Matches(app) && place.Matches(app) {
+			visible = append(visible, app)
+		}
+	}
+	return visible, nil
+}
+
+// shareLink is a sharing link with its URL, for the settings page
+type shareLink struct {
+	models.Share
+	URL string
+}
+
+// HtmxSharesHandler lists, creates and revokes the current user's sharing links
+func HtmxSharesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.UserFromContext(ctx)
+	data := map[string]interface{}{}
+
+	switch {
+	case r.Method == http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Invalid form data", http.StatusBadRequest)
+			return
+		}
+		filter := models.ShareFilter{Status: r.FormValue("status")}
+		for _, tag := range strings.Split(r.FormValue("tags"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+		days, _ := strconv.Atoi(r.FormValue("expiresInDays"))
+		ttl := time.Duration(days) * 24 * time.Hour
+		if _, err := auth.IssueShare(ctx, user, r.FormValue("name"), filter, ttl); err != nil {
+			data["Error"] = err.Error()
+		}
+
+	case r.Method == http.MethodDelete:
+		id := strings.TrimPrefix(r.URL.Path, "/htmx/shares/")
+		if err := storage.RevokeShare(ctx, user.ID, id); err != nil {
+			slog.WarnContext(ctx, "failed to revoke share", "shareId", id, "error", err)
+			data["Error"] = "Failed to revoke sharing link"
+		}
+
+	case r.Method != http.MethodGet:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	shares, err := storage.ListShares(ctx, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve sharing links", http.StatusInternalServerError)
+		return
+	}
+
+	links := make([]shareLink, 0, len(shares))
+	for _, share := range shares {
+		token, err := auth.ShareToken(&share)
+		if err != nil {
+			http.Error(w, "Failed to sign sharing links", http.StatusInternalServerError)
+			return
+		}
+		links = append(links, shareLink{Share: share, URL: "/share/" + token})
+	}
+	data["Shares"] = links
+	data["Now"] = time.Now()
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/shares/list.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
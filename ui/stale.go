@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// AttentionHandler handles the page listing the applications that need attention
+// because they haven't been updated for a while
+func AttentionHandler(w http.ResponseWriter, r *http.Request) {
+	applications, err := storage.ListStaleApplications(r.Context(), workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
+		return
+	}
+	settings, err := storage.GetStaleSettings(r.Context(), workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve stale settings", http.StatusInternalServerError)
+		return
+	}
+
+	renderTemplate(w, r, "attention", TemplateData{
+		Title:         "Needs Attention",
+		Applications:  applications,
+		StaleSettings: settings,
+		ReadOnly:      !auth.HasRole(r.Context(), models.RoleEditor),
+	})
+}
+
+// staleDaysFromForm reads a number of days from a form field, treating an empty field as 0
+func staleDaysFromForm(r *http.Request, field string) (int, bool) {
+	value := r.FormValue(field)
+	if value == "" {
+		return 0, true
+	}
+	days, err := strconv.Atoi(value)
+	return days, err == nil
+}
+
+// HtmxStaleSettingsHandler shows and replaces the stale settings on the settings page:
+//
+//	GET /htmx/stale/settings   show the thresholds
+//	PUT /htmx/stale/settings   replace them with the ones in the form
+func HtmxStaleSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	canEdit := auth.HasRole(ctx, models.RoleEditor)
+	data := map[string]interface{}{
+		"CanEdit": canEdit,
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+
+	case http.MethodPut:
+		if !canEdit {
+			http.Error(w, "Your role in this workspace does not allow changing stale settings", http.StatusForbidden)
+			return
+		}
+		settings := &models.StaleSettings{WorkspaceID: workspaceID(r), Days: map[string]int{}}
+		applied, ok1 := staleDaysFromForm(r, "applied")
+		inProgress, ok2 := staleDaysFromForm(r, "in_progress")
+		ghostAfter, ok3 := staleDaysFromForm(r, "ghostAfterDays")
+		if !ok1 || !ok2 || !ok3 {
+			data["Error"] = "Days must be whole numbers"
+			break
+		}
+		settings.Days[models.ApplicationStatus.Applied] = applied
+		settings.Days[models.ApplicationStatus.InProgress] = inProgress
+		settings.GhostAfterDays = ghostAfter
+		if err := settings.Validate(); err != nil {
+			data["Error"] = err.Error()
+		} else if err := storage.SaveStaleSettings(ctx, settings); err != nil {
+			slog.ErrorContext(ctx, "failed to save stale settings", "error", err)
+			data["Error"] = "Failed to save stale settings"
+		} else {
+			data["Saved"] = true
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	settings, err := storage.GetStaleSettings(ctx, workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve stale settings", http.StatusInternalServerError)
+		return
+	}
+	data["Settings"] = settings
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/stale/settings.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
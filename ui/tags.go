@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"html/template"
+	"net/http"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// HtmxTagsHandler lists the tags of the current workspace; editors get controls that
+// rename, merge and color tags through the API
+func HtmxTagsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tags, err := storage.ListTags(r.Context(), workspaceID(r))
+	if err != nil {
+		http.Error(w, "Failed to retrieve tags", http.StatusInternalServerError)
+		return
+	}
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/tags/list.html"))
+	err = tmpl.Execute(w, map[string]interface{}{
+		"Tags":    tags,
+		"Colors":  models.TagColors,
+		"CanEdit": auth.HasRole(r.Context(), models.RoleEditor),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
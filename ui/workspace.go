@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"ApplicationTracker/auth"
+	"ApplicationTracker/models"
+	"ApplicationTracker/storage"
+)
+
+// auditPageSize is the number of audit events shown on the settings page
+const auditPageSize = 50
+
+// auditActions describes audit event actions for display
+var auditActions = map[string]string{
+	models.AuditApplicationCreated:       "added",
+	models.AuditApplicationUpdated:       "edited",
+	models.AuditApplicationStatusChanged: "changed the status of",
+	models.AuditApplicationDeleted:       "deleted",
+	models.AuditApplicationMerged:        "merged a duplicate into",
+	models.AuditCommentCreated:           "commented on",
+	models.AuditNoteCreated:              "added a note to",
+	models.AuditNoteUpdated:              "edited a note on",
+	models.AuditNoteDeleted:              "deleted a note from",
+	models.AuditInterviewScheduled:       "scheduled an interview for",
+	models.AuditInterviewUpdated:         "updated an interview for",
+	models.AuditInterviewDeleted:         "deleted an interview for",
+	models.AuditContactCreated:           "added contact",
+	models.AuditContactUpdated:           "edited contact",
+	models.AuditContactDeleted:           "deleted contact",
+	models.AuditContactLinked:            "linked a contact to",
+	models.AuditContactUnlinked:          "unlinked a contact from",
+	models.AuditReminderCreated:          "added a reminder to",
+	models.AuditReminderUpdated:          "updated a reminder on",
+	models.AuditReminderDeleted:          "deleted a reminder from",
+	models.AuditCompanyCreated:           "added company",
+	models.AuditCompanyUpdated:           "edited company",
+	models.AuditCompanyDeleted:           "deleted company",
+	models.AuditCompaniesMerged:          "merged a company into",
+	models.AuditTagRenamed:               "renamed a tag to",
+	models.AuditTagsMerged:               "merged tags into",
+	models.AuditMemberAdded:              "added member",
+	models.AuditMemberRoleChanged:        "changed the role of member",
+	models.AuditMemberRemoved:            "removed member",
+}
+
+// auditEntry is an audit event prepared for display
+type auditEntry struct {
+	models.AuditEvent
+	Description string
+	Target      string
+}
+
+// SelectWorkspaceHandler switches the workspace the browser acts on
+func SelectWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := auth.SelectWorkspace(w, r, r.FormValue("workspace")); err != nil {
+		if !errors.Is(err, auth.ErrNotMember) {
+			slog.ErrorContext(r.Context(), "failed to select workspace", "error", err)
+		}
+		http.Error(w, "You are not a member of that workspace", http.StatusForbidden)
+		return
+	}
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/applications")
+		return
+	}
+	http.Redirect(w, r, "/applications", http.StatusSeeOther)
+}
+
+// HtmxCommentsHandler lists and adds comments on /htmx/applications/{id}/comments
+func HtmxCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := strings.TrimPrefix(r.URL.Path, "/htmx/applications/")
+	id, found := strings.CutSuffix(id, "/comments")
+	if !found || id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	if _, err := storage.GetApplicationByID(ctx, workspaceID(r), id); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Application not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to retrieve application", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	canComment := auth.HasRole(ctx, models.RoleCommenter)
+	data := map[string]interface{}{
+		"ApplicationID": id,
+		"CanComment":    canComment,
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !canComment {
+			http.Error(w, "Your role in this workspace does not allow commenting", http.StatusForbidden)
+			return
+		}
+		body := strings.TrimSpace(r.FormValue("body"))
+		if body == "" || len(body) > models.MaxCommentLength {
+			data["Error"] = fmt.Sprintf("Comment must be between 1 and %d characters", models.MaxCommentLength)
+			break
+		}
+		comment := models.NewComment(workspaceID(r), id, auth.UserFromContext(ctx), body)
+		if err := storage.CreateComment(ctx, comment); err != nil {
+			slog.ErrorContext(ctx, "failed to save comment", "error", err)
+			data["Error"] = "Failed to save comment"
+			break
+		}
+		auth.Audit(ctx, comment.WorkspaceID, models.AuditCommentCreated, id, "")
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	comments, err := storage.ListComments(ctx, workspaceID(r), id)
+	if err != nil {
+		http.Error(w, "Failed to retrieve comments", http.StatusInternalServerError)
+		return
+	}
+	data["Comments"] = comments
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/comments/list.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// HtmxMembersHandler lists, adds and removes members of the current user's own workspace
+func HtmxMembersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.UserFromContext(ctx)
+	data := map[string]interface{}{
+		"Roles": models.MemberRoles,
+	}
+
+	switch {
+	case r.Method == http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Invalid form data", http.StatusBadRequest)
+			return
+		}
+		if _, err := auth.AddMember(ctx, user, r.FormValue("username"), r.FormValue("role")); err != nil {
+			data["Error"] = err.Error()
+		}
+
+	case r.Method == http.MethodDelete:
+		memberID := strings.TrimPrefix(r.URL.Path, "/htmx/members/")
+		if err := auth.RemoveMember(ctx, user, memberID); err != nil {
+			slog.WarnContext(ctx, "failed to remove member", "userId", memberID, "error", err)
+			data["Error"] = "Failed to remove member"
+		}
+
+	case r.Method != http.MethodGet:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	members, err := storage.ListMembers(ctx, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve members", http.StatusInternalServerError)
+		return
+	}
+	data["Members"] = members
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/members/list.html"))
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// HtmxAuditHandler shows recent activity in the current user's own workspace
+func HtmxAuditHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.UserFromContext(ctx)
+
+	events, err := storage.ListAuditEvents(ctx, user.ID, auditPageSize)
+	if err != nil {
+		http.Error(w, "Failed to retrieve activity", http.StatusInternalServerError)
+		return
+	}
+	applications, err := storage.GetAllApplications(ctx, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve applications", http.StatusInternalServerError)
+		return
+	}
+	names := make(map[string]string, len(applications))
+	for _, app := range applications {
+		names[app.ID] = app.Company + " - " + app.Position
+	}
+
+	entries := make([]auditEntry, 0, len(events))
+	for _, event := range events {
+		entry := auditEntry{AuditEvent: event, Description: auditActions[event.Action]}
+		if entry.Description == "" {
+			entry.Description = event.Action
+		}
+		if strings.HasPrefix(event.Action, "member.") {
+			entry.Target = event.Detail
+			entry.Detail = ""
+		} else if event.Action == models.AuditContactCreated || event.Action == models.AuditContactUpdated ||
+			event.Action == models.AuditContactDeleted || strings.HasPrefix(event.Action, "company.") {
+			entry.Target = event.Detail
+			entry.Detail = ""
+		} else if strings.HasPrefix(event.Action, "tag.") {
+			entry.Target = event.TargetID
+		} else if name, ok := names[event.TargetID]; ok {
+			entry.Target = name
+		} else {
+			entry.Target = "a deleted application"
+		}
+		entries = append(entries, entry)
+	}
+
+	tmpl := template.Must(template.ParseFiles("templates/htmx/audit/list.html"))
+	if err := tmpl.Execute(w, map[string]interface{}{"Events": entries}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}